@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/uiez/uikit/tools/i18nfile"
+	"github.com/uiez/uikit/tools/po"
+	"github.com/uiez/uikit/tools/xliff"
+)
+
+func init() {
+	commands = append(commands,
+		command{"export", "<file.json>", "export a language for translation as XLIFF 2.0 or gettext PO", runExport},
+		command{"import", "<file.json> <translated>", "import a translated XLIFF or PO file into a language file", runImport},
+	)
+}
+
+// formatOf returns the exchange format of path by its extension if format is
+// empty.
+func formatOf(format, path string) (string, error) {
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(path), ".")
+	}
+	switch format {
+	case "xlf", "xliff":
+		return "xliff", nil
+	case "po", "pot":
+		return "po", nil
+	}
+	return "", fmt.Errorf("unknown format %q, use -format xliff or po", format)
+}
+
+func loadVariant(base, lang string) (*i18nfile.File, error) {
+	f, err := i18nfile.Load(i18nfile.VariantPath(base, lang))
+	if os.IsNotExist(err) {
+		return i18nfile.New(), nil
+	}
+	return f, err
+}
+
+func runExport(fs *flag.FlagSet, args []string) error {
+	src := fs.String("src", "en", "source language")
+	lang := fs.String("lang", "", "target language, empty for a source only XLIFF file")
+	format := fs.String("format", "", "xliff or po, by default from the -o extension")
+	out := fs.String("o", "", "output file, standard output if empty")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errFailed
+	}
+	f, err := formatOf(*format, *out)
+	if err != nil {
+		return err
+	}
+	base := fs.Arg(0)
+	source, err := i18nfile.Load(i18nfile.VariantPath(base, *src))
+	if err != nil {
+		return err
+	}
+	var target *i18nfile.File
+	if *lang != "" {
+		if target, err = loadVariant(base, *lang); err != nil {
+			return err
+		}
+	}
+
+	var buf bytes.Buffer
+	switch f {
+	case "xliff":
+		id := strings.TrimSuffix(filepath.Base(base), filepath.Ext(base))
+		err = xliff.Encode(&buf, &xliff.Document{ID: id, SrcLang: *src, TrgLang: *lang, Source: source, Target: target})
+	case "po":
+		if target == nil {
+			return fmt.Errorf("po export needs -lang")
+		}
+		err = po.Encode(&buf, &po.Document{SrcLang: *src, Lang: *lang, Source: source, Target: target})
+	}
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		_, err = io.Copy(os.Stdout, &buf)
+		return err
+	}
+	return os.WriteFile(*out, buf.Bytes(), 0o644)
+}
+
+func runImport(fs *flag.FlagSet, args []string) error {
+	format := fs.String("format", "", "xliff or po, by default from the file extension")
+	lang := fs.String("lang", "", "target language, by default from the translated file")
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return errFailed
+	}
+	base, path := fs.Arg(0), fs.Arg(1)
+	f, err := formatOf(*format, path)
+	if err != nil {
+		return err
+	}
+	r, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	var (
+		docLang string
+		target  *i18nfile.File
+	)
+	switch f {
+	case "xliff":
+		doc, err := xliff.Decode(r)
+		if err != nil {
+			return err
+		}
+		docLang, target = doc.TrgLang, doc.Target
+	case "po":
+		doc, err := po.Decode(r)
+		if err != nil {
+			return err
+		}
+		docLang, target = doc.Lang, doc.Target
+	}
+	if *lang == "" {
+		*lang = docLang
+	}
+	if *lang == "" || target == nil {
+		return fmt.Errorf("%s has no target language", path)
+	}
+
+	dst, err := loadVariant(base, *lang)
+	if err != nil {
+		return err
+	}
+	for key, msg := range target.Messages {
+		dst.Messages[key] = msg
+		if n, ok := target.Notes[key]; ok {
+			dst.Notes[key] = n
+		} else {
+			delete(dst.Notes, key)
+		}
+	}
+	p := i18nfile.VariantPath(base, *lang)
+	if err := dst.Save(p); err != nil {
+		return err
+	}
+	fmt.Println("updated", p)
+	return nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/uiez/uikit/tools/i18nextract"
+	"github.com/uiez/uikit/tools/i18nfile"
+)
+
+func init() {
+	commands = append(commands,
+		command{"extract", "[dir]", "add keys used in code to the translation files", runExtract},
+		command{"report", "[dir]", "report missing and unused keys per language", runReport},
+		command{"pseudo", "[dir]", "generate a pseudo-localized language variant", runPseudo},
+	)
+}
+
+func runExtract(fs *flag.FlagSet, args []string) error {
+	langs := fs.String("lang", "", "comma separated languages to create files for")
+	prune := fs.Bool("prune", false, "remove keys that are not used in code")
+	fs.Parse(args)
+	dir, err := dirArg(fs)
+	if err != nil {
+		return err
+	}
+	r, err := i18nextract.Scan(dir)
+	if err != nil {
+		return err
+	}
+	written, err := r.Update(i18nextract.UpdateOptions{Langs: splitList(*langs), RemoveUnused: *prune})
+	if err != nil {
+		return err
+	}
+	for _, p := range written {
+		fmt.Println("updated", p)
+	}
+	return nil
+}
+
+func runReport(fs *flag.FlagSet, args []string) error {
+	strict := fs.Bool("strict", false, "exit with failure if any key is missing")
+	fs.Parse(args)
+	dir, err := dirArg(fs)
+	if err != nil {
+		return err
+	}
+	r, err := i18nextract.Scan(dir)
+	if err != nil {
+		return err
+	}
+	reports, err := r.Report()
+	if err != nil {
+		return err
+	}
+	if writeReport(os.Stdout, r, reports) && *strict {
+		return errFailed
+	}
+	return nil
+}
+
+// writeReport prints reports and returns whether any key is missing.
+func writeReport(w io.Writer, r *i18nextract.Result, reports []i18nextract.CatalogReport) bool {
+	missing := false
+	for _, cr := range reports {
+		fmt.Fprintf(w, "%s (%s)\n", cr.Catalog.Name, cr.Catalog.Path)
+		if len(cr.Locales) == 0 {
+			fmt.Fprintln(w, "  no language files")
+		}
+		for _, lr := range cr.Locales {
+			if len(lr.Missing) == 0 && len(lr.Unused) == 0 {
+				fmt.Fprintf(w, "  %s: ok\n", lr.Lang)
+				continue
+			}
+			var parts []string
+			if len(lr.Missing) > 0 {
+				missing = true
+				parts = append(parts, "missing: "+strings.Join(lr.Missing, ", "))
+			}
+			if len(lr.Unused) > 0 {
+				parts = append(parts, "unused: "+strings.Join(lr.Unused, ", "))
+			}
+			fmt.Fprintf(w, "  %s: %s\n", lr.Lang, strings.Join(parts, "; "))
+		}
+	}
+	if len(r.Strs) > 0 {
+		fmt.Fprintln(w, "untranslated i18n.Str text:")
+		for _, u := range r.Strs {
+			fmt.Fprintf(w, "  %s: %s\n", u.Pos, u.Call)
+		}
+	}
+	if len(r.Dynamic) > 0 {
+		fmt.Fprintln(w, "non-constant arguments:")
+		for _, u := range r.Dynamic {
+			fmt.Fprintf(w, "  %s: %s\n", u.Pos, u.Call)
+		}
+	}
+	return missing
+}
+
+func runPseudo(fs *flag.FlagSet, args []string) error {
+	from := fs.String("from", "en", "language to pseudo-localize")
+	lang := fs.String("lang", i18nextract.PseudoLang, "language of the generated variant")
+	fs.Parse(args)
+	dir, err := dirArg(fs)
+	if err != nil {
+		return err
+	}
+	r, err := i18nextract.Scan(dir)
+	if err != nil {
+		return err
+	}
+	for _, c := range r.Catalogs {
+		if c.Path == "" {
+			continue
+		}
+		src, err := i18nfile.Load(i18nfile.VariantPath(c.Path, *from))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		dst, err := i18nextract.Pseudo(src)
+		if err != nil {
+			return err
+		}
+		p := i18nfile.VariantPath(c.Path, *lang)
+		if err := dst.Save(p); err != nil {
+			return err
+		}
+		fmt.Println("wrote", p)
+	}
+	return nil
+}
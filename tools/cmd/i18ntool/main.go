@@ -0,0 +1,81 @@
+// Command i18ntool maintains the JSON translation files loaded by i18n.File.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+type command struct {
+	name  string
+	args  string
+	short string
+	run   func(fs *flag.FlagSet, args []string) error
+}
+
+var commands []command
+
+// errFailed exits with a failure status after the command reported why.
+var errFailed = errors.New("failed")
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: i18ntool <command> [flags] [args]")
+	fmt.Fprintln(os.Stderr, "\ncommands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", c.name, c.short)
+	}
+	fmt.Fprintln(os.Stderr, "\nrun 'i18ntool <command> -h' for the flags of a command")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	name := os.Args[1]
+	for _, c := range commands {
+		if c.name != name {
+			continue
+		}
+		fs := flag.NewFlagSet(c.name, flag.ExitOnError)
+		fs.Usage = func() {
+			fmt.Fprintf(os.Stderr, "usage: i18ntool %s [flags] %s\n\n%s\n\nflags:\n", c.name, c.args, c.short)
+			fs.PrintDefaults()
+		}
+		err := c.run(fs, os.Args[2:])
+		if err == nil {
+			return
+		}
+		if err != errFailed {
+			fmt.Fprintln(os.Stderr, "i18ntool "+c.name+":", err)
+		}
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "i18ntool: unknown command %q\n", name)
+	usage()
+	os.Exit(2)
+}
+
+func splitList(s string) []string {
+	var list []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// dirArg returns the optional directory argument, defaulting to ".".
+func dirArg(fs *flag.FlagSet) (string, error) {
+	switch fs.NArg() {
+	case 0:
+		return ".", nil
+	case 1:
+		return strings.TrimSuffix(fs.Arg(0), "/..."), nil
+	}
+	return "", fmt.Errorf("too many arguments")
+}
@@ -0,0 +1,3 @@
+module github.com/uiez/uikit/tools
+
+go 1.18
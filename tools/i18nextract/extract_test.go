@@ -0,0 +1,180 @@
+package i18nextract
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/uiez/uikit/tools/i18nfile"
+	"github.com/uiez/uikit/tools/msgformat"
+)
+
+func positions(us []Usage) []string {
+	var list []string
+	for _, u := range us {
+		list = append(list, fmt.Sprintf("%s:%d %s", u.Pos.Filename, u.Pos.Line, u.Text))
+	}
+	return list
+}
+
+func TestScanExample(t *testing.T) {
+	r, err := Scan("../../example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Catalogs) != 1 {
+		t.Fatalf("got %d catalogs, want 1", len(r.Catalogs))
+	}
+	c := r.Catalog("github.com/uiez/uikit/example/assets.AppI18n")
+	if c == nil {
+		t.Fatalf("catalog AppI18n not found in %v", r.Catalogs)
+	}
+	if want := filepath.FromSlash("../../example/assets/i18n/app.json"); c.Path != want {
+		t.Errorf("got path %q, want %q", c.Path, want)
+	}
+	hello := c.Keys["hello"]
+	if len(c.Keys) != 1 || len(hello) != 1 || hello[0].Filename != "pkg/i18n/app.go" || hello[0].Line != 11 {
+		t.Errorf("unexpected keys %v", c.Keys)
+	}
+
+	wantStrs := []string{
+		"pkg/edit/edit.go:70 Choose file to open",
+		"pkg/edit/edit.go:80 Choose file to save",
+		"pkg/tray/tray.go:108 choose tray icon",
+		"pkg/window/window.go:66 Transparent window",
+	}
+	if got := positions(r.Strs); !reflect.DeepEqual(got, wantStrs) {
+		t.Errorf("got Str usages %q, want %q", got, wantStrs)
+	}
+	wantDynamic := []string{"cmd/app/main_desktop.go:52 "}
+	if got := positions(r.Dynamic); !reflect.DeepEqual(got, wantDynamic) {
+		t.Errorf("got dynamic usages %q, want %q", got, wantDynamic)
+	}
+	if r.Dynamic[0].Call != "i18n.Str(title)" {
+		t.Errorf("got call %q", r.Dynamic[0].Call)
+	}
+
+	reports, err := r.Report()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 1 || len(reports[0].Locales) != 2 {
+		t.Fatalf("unexpected reports %+v", reports)
+	}
+	for _, lr := range reports[0].Locales {
+		if len(lr.Missing) != 0 || len(lr.Unused) != 0 {
+			t.Errorf("%s: unexpected report %+v", lr.Lang, lr)
+		}
+	}
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod": "module example.com/m\n",
+		"assets/assets.go": `package assets
+
+import (
+	"github.com/uiez/uikit/core/corebase"
+	"github.com/uiez/uikit/modules/locales/i18n"
+)
+
+var Texts = i18n.File(corebase.PkgFile("i18n/texts.json"))
+
+func Title() string { return Texts.Tr("title") }
+`,
+		"assets/i18n/texts-en.json": `{"messages": {"title": "Title", "stale": "Stale"}}`,
+		"ui/ui.go": `package ui
+
+import (
+	res "example.com/m/assets"
+	"github.com/uiez/uikit/modules/locales/i18n"
+)
+
+var (
+	_ = res.Texts.Tr("greeting")
+	_ = res.Texts.Tr("files." + "count")
+	_ = i18n.Str("literal")
+)
+`,
+	})
+
+	r, err := Scan(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reports, err := r.Report()
+	if err != nil {
+		t.Fatal(err)
+	}
+	en := reports[0].Locales[0]
+	if !reflect.DeepEqual(en.Missing, []string{"files.count", "greeting"}) || !reflect.DeepEqual(en.Unused, []string{"stale"}) {
+		t.Errorf("unexpected report %+v", en)
+	}
+
+	written, err := r.Update(UpdateOptions{Langs: []string{"ja"}, RemoveUnused: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(written) != 2 {
+		t.Errorf("got written files %v", written)
+	}
+	for lang, want := range map[string]map[string]string{
+		"en": {"title": "Title", "greeting": "", "files.count": ""},
+		"ja": {"title": "", "greeting": "", "files.count": ""},
+	} {
+		f, err := i18nfile.Load(filepath.Join(dir, "assets/i18n/texts-"+lang+".json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(f.Messages, want) {
+			t.Errorf("%s: got %v, want %v", lang, f.Messages, want)
+		}
+	}
+
+	written, err = r.Update(UpdateOptions{})
+	if err != nil || len(written) != 0 {
+		t.Errorf("second update wrote %v, %v", written, err)
+	}
+}
+
+func TestPseudo(t *testing.T) {
+	src, err := i18nfile.Load("../../example/assets/i18n/app-en.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src.Messages["files"] = "{count, plural, one {# file} other {# files in {dir}}}"
+	src.Messages["empty"] = ""
+	f, err := Pseudo(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := f.Messages["hello"]; got != "[Ĥéļļö Î18ñ ~~~~]" {
+		t.Errorf("got %q", got)
+	}
+	if f.Messages["empty"] != "" {
+		t.Errorf("untranslated message was pseudo-localized: %q", f.Messages["empty"])
+	}
+	got, err := msgformat.Format(PseudoLang, f.Messages["files"], msgformat.Args{"count": 2, "dir": "src"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(got, "[2 ƒîļéš îñ src ~") {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,91 @@
+package i18nextract
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/uiez/uikit/tools/i18nfile"
+	"github.com/uiez/uikit/tools/msgformat"
+)
+
+// PseudoLang is the conventional pseudo-locale tag for accented, expanded text.
+const PseudoLang = "en-XA"
+
+var accents = map[rune]rune{
+	'a': 'á', 'b': 'ƀ', 'c': 'ç', 'd': 'ð', 'e': 'é', 'f': 'ƒ', 'g': 'ĝ', 'h': 'ĥ', 'i': 'î',
+	'j': 'ĵ', 'k': 'ķ', 'l': 'ļ', 'm': 'ɱ', 'n': 'ñ', 'o': 'ö', 'p': 'þ', 'q': 'ǫ', 'r': 'ŕ',
+	's': 'š', 't': 'ţ', 'u': 'û', 'v': 'ṽ', 'w': 'ŵ', 'x': 'ẋ', 'y': 'ý', 'z': 'ž',
+	'A': 'Å', 'B': 'Ɓ', 'C': 'Ç', 'D': 'Ð', 'E': 'É', 'F': 'Ƒ', 'G': 'Ĝ', 'H': 'Ĥ', 'I': 'Î',
+	'J': 'Ĵ', 'K': 'Ķ', 'L': 'Ļ', 'M': 'Ṁ', 'N': 'Ñ', 'O': 'Ö', 'P': 'Þ', 'Q': 'Ǫ', 'R': 'Ŕ',
+	'S': 'Š', 'T': 'Ţ', 'U': 'Û', 'V': 'Ṽ', 'W': 'Ŵ', 'X': 'Ẋ', 'Y': 'Ý', 'Z': 'Ž',
+}
+
+// PseudoMessage accents the text of an ICU message, leaves its arguments
+// intact, and wraps it in brackets padded by about 40% so that truncated or
+// concatenated strings stand out in layouts.
+func PseudoMessage(msg string) (string, error) {
+	nodes, err := msgformat.Parse(msg)
+	if err != nil {
+		return "", err
+	}
+	nodes = accentNodes(nodes)
+	padding := (utf8.RuneCountInString(msg)*2 + 4) / 5
+	nodes = append([]msgformat.Node{msgformat.Text("[")}, nodes...)
+	nodes = append(nodes, msgformat.Text(" "+strings.Repeat("~", padding)+"]"))
+	return msgformat.Print(nodes), nil
+}
+
+func accentNodes(nodes []msgformat.Node) []msgformat.Node {
+	out := make([]msgformat.Node, len(nodes))
+	for i, n := range nodes {
+		switch n := n.(type) {
+		case msgformat.Text:
+			out[i] = msgformat.Text(strings.Map(func(r rune) rune {
+				if a, ok := accents[r]; ok {
+					return a
+				}
+				return r
+			}, string(n)))
+		case msgformat.Plural:
+			n.Cases = accentCases(n.Cases)
+			out[i] = n
+		case msgformat.Select:
+			n.Cases = accentCases(n.Cases)
+			out[i] = n
+		default:
+			out[i] = n
+		}
+	}
+	return out
+}
+
+func accentCases(cases []msgformat.Case) []msgformat.Case {
+	out := make([]msgformat.Case, len(cases))
+	for i, c := range cases {
+		c.Nodes = accentNodes(c.Nodes)
+		out[i] = c
+	}
+	return out
+}
+
+// Pseudo returns a pseudo-localized copy of src. Untranslated messages stay
+// empty and notes are kept.
+func Pseudo(src *i18nfile.File) (*i18nfile.File, error) {
+	dst := i18nfile.New()
+	for _, key := range src.Keys() {
+		if src.Messages[key] == "" {
+			dst.Messages[key] = ""
+			continue
+		}
+		msg, err := PseudoMessage(src.Messages[key])
+		if err != nil {
+			return nil, fmt.Errorf("message %q: %w", key, err)
+		}
+		dst.Messages[key] = msg
+	}
+	for key, note := range src.Notes {
+		dst.Notes[key] = note
+	}
+	return dst, nil
+}
@@ -0,0 +1,140 @@
+package i18nextract
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/uiez/uikit/tools/i18nfile"
+)
+
+// LocaleReport compares a language variant of a catalog with the keys used
+// in code.
+type LocaleReport struct {
+	Lang    string
+	Path    string
+	Missing []string // used in code but absent or empty
+	Unused  []string // present but never used
+}
+
+// CatalogReport lists the language variants of a catalog.
+type CatalogReport struct {
+	Catalog *Catalog
+	Locales []LocaleReport // sorted by language
+}
+
+// Report loads the language variants of each catalog and compares them with
+// the keys used in code.
+func (r *Result) Report() ([]CatalogReport, error) {
+	var reports []CatalogReport
+	for _, c := range r.Catalogs {
+		if c.Path == "" {
+			continue
+		}
+		variants, err := i18nfile.Variants(c.Path)
+		if err != nil {
+			return nil, err
+		}
+		cr := CatalogReport{Catalog: c}
+		for _, lang := range sortedLangs(variants) {
+			f, err := i18nfile.Load(variants[lang])
+			if err != nil {
+				return nil, err
+			}
+			lr := compare(c, f)
+			lr.Lang = lang
+			lr.Path = variants[lang]
+			cr.Locales = append(cr.Locales, lr)
+		}
+		reports = append(reports, cr)
+	}
+	return reports, nil
+}
+
+func sortedLangs(variants map[string]string) []string {
+	langs := make([]string, 0, len(variants))
+	for lang := range variants {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+func compare(c *Catalog, f *i18nfile.File) LocaleReport {
+	var lr LocaleReport
+	for _, key := range c.SortedKeys() {
+		if f.Messages[key] == "" {
+			lr.Missing = append(lr.Missing, key)
+		}
+	}
+	for _, key := range f.Keys() {
+		if _, ok := c.Keys[key]; !ok {
+			lr.Unused = append(lr.Unused, key)
+		}
+	}
+	return lr
+}
+
+// UpdateOptions controls Update.
+type UpdateOptions struct {
+	Langs        []string // languages to create when they have no file yet
+	RemoveUnused bool
+}
+
+// Update adds the keys used in code to every language variant of each
+// catalog, as empty messages that Report lists as missing until translated.
+// It returns the paths of the written files.
+func (r *Result) Update(opts UpdateOptions) ([]string, error) {
+	var written []string
+	for _, c := range r.Catalogs {
+		if c.Path == "" {
+			continue
+		}
+		variants, err := i18nfile.Variants(c.Path)
+		if err != nil {
+			return nil, err
+		}
+		for _, lang := range opts.Langs {
+			if _, ok := variants[lang]; !ok {
+				variants[lang] = i18nfile.VariantPath(c.Path, lang)
+			}
+		}
+		for _, lang := range sortedLangs(variants) {
+			p := variants[lang]
+			f, err := i18nfile.Load(p)
+			if os.IsNotExist(err) {
+				f, err = i18nfile.New(), nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			changed := false
+			for key := range c.Keys {
+				if _, ok := f.Messages[key]; !ok {
+					f.Messages[key] = ""
+					changed = true
+				}
+			}
+			if opts.RemoveUnused {
+				for key := range f.Messages {
+					if _, ok := c.Keys[key]; !ok {
+						delete(f.Messages, key)
+						delete(f.Notes, key)
+						changed = true
+					}
+				}
+			}
+			if _, err := os.Stat(p); os.IsNotExist(err) {
+				changed = true
+			}
+			if !changed {
+				continue
+			}
+			if err := f.Save(p); err != nil {
+				return nil, fmt.Errorf("write %s: %w", p, err)
+			}
+			written = append(written, p)
+		}
+	}
+	return written, nil
+}
@@ -0,0 +1,374 @@
+// Package i18nextract statically finds i18n usages in Go packages: the
+// translation files declared with i18n.File, the keys looked up with their Tr
+// method, and the untranslated text wrapped by i18n.Str.
+package i18nextract
+
+import (
+	"bufio"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	i18nPkgPath     = "github.com/uiez/uikit/modules/locales/i18n"
+	corebasePkgPath = "github.com/uiez/uikit/core/corebase"
+)
+
+// Catalog is a translation file declared as a package level variable, like
+//
+//	var AppI18n = i18n.File(corebase.PkgFile("i18n/app.json"))
+type Catalog struct {
+	Name string // qualified variable name, "<import path>.<var>"
+	Path string // file path of the declared base file, empty if not constant
+	Pos  token.Position
+
+	Keys map[string][]token.Position // keys passed to Tr
+}
+
+// SortedKeys returns the keys used with the catalog.
+func (c *Catalog) SortedKeys() []string {
+	keys := make([]string, 0, len(c.Keys))
+	for k := range c.Keys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Usage is a call found in source.
+type Usage struct {
+	Call string // the call as written, like `i18n.Str("choose tray icon")`
+	Text string // constant argument, empty for dynamic usages
+	Pos  token.Position
+}
+
+// Result is the outcome of a scan.
+type Result struct {
+	Catalogs []*Catalog // sorted by name
+	Strs     []Usage    // i18n.Str calls with constant text
+	Dynamic  []Usage    // Tr and i18n.Str calls whose argument isn't constant
+}
+
+// Catalog returns the catalog with the qualified name, or nil.
+func (r *Result) Catalog(name string) *Catalog {
+	for _, c := range r.Catalogs {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+type pkgFiles struct {
+	dir        string
+	importPath string
+	files      []*ast.File
+}
+
+// Scan parses the Go packages under root, like "root/...", ignoring test
+// files, testdata and vendor directories. Files excluded by build tags are
+// scanned too, so that usages on every platform are found.
+func Scan(root string) (*Result, error) {
+	fset := token.NewFileSet()
+	var pkgs []*pkgFiles
+	err := filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		name := d.Name()
+		if p != root && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" || name == "vendor") {
+			return filepath.SkipDir
+		}
+		pkg, err := parseDir(fset, p)
+		if err != nil {
+			return err
+		}
+		if pkg != nil {
+			pkgs = append(pkgs, pkg)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s := scanner{fset: fset, root: root, catalogs: make(map[string]*Catalog)}
+	for _, pkg := range pkgs {
+		s.findCatalogs(pkg)
+	}
+	for _, pkg := range pkgs {
+		s.findUsages(pkg)
+	}
+	r := &Result{Strs: s.strs, Dynamic: s.dynamic}
+	for _, c := range s.catalogs {
+		r.Catalogs = append(r.Catalogs, c)
+	}
+	sort.Slice(r.Catalogs, func(i, j int) bool { return r.Catalogs[i].Name < r.Catalogs[j].Name })
+	sortUsages(r.Strs)
+	sortUsages(r.Dynamic)
+	return r, nil
+}
+
+func sortUsages(us []Usage) {
+	sort.Slice(us, func(i, j int) bool {
+		a, b := us[i].Pos, us[j].Pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+}
+
+func parseDir(fset *token.FileSet, dir string) (*pkgFiles, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []*ast.File
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	if len(files) == 0 {
+		return nil, nil
+	}
+	importPath, err := dirImportPath(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &pkgFiles{dir: dir, importPath: importPath, files: files}, nil
+}
+
+// dirImportPath derives the import path of dir from the nearest go.mod.
+func dirImportPath(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for d := abs; ; {
+		if mod := modulePath(filepath.Join(d, "go.mod")); mod != "" {
+			rel, err := filepath.Rel(d, abs)
+			if err != nil {
+				return "", err
+			}
+			return path.Join(mod, filepath.ToSlash(rel)), nil
+		}
+		parent := filepath.Dir(d)
+		if parent == d {
+			return filepath.ToSlash(abs), nil
+		}
+		d = parent
+	}
+}
+
+func modulePath(gomod string) string {
+	f, err := os.Open(gomod)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if strings.HasPrefix(line, "module") {
+			mod := strings.TrimSpace(strings.TrimPrefix(line, "module"))
+			if unquoted, err := strconv.Unquote(mod); err == nil {
+				mod = unquoted
+			}
+			return mod
+		}
+	}
+	return ""
+}
+
+type scanner struct {
+	fset     *token.FileSet
+	root     string
+	catalogs map[string]*Catalog
+	strs     []Usage
+	dynamic  []Usage
+}
+
+// fileImports maps the local names of a file's imports to their paths.
+func fileImports(f *ast.File) map[string]string {
+	imports := make(map[string]string)
+	for _, spec := range f.Imports {
+		p, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := path.Base(p)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imports[name] = p
+	}
+	return imports
+}
+
+// pkgCall reports whether expr calls the function pkgPath.name.
+func pkgCall(expr ast.Expr, imports map[string]string, pkgPath, name string) (*ast.CallExpr, bool) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return nil, false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return nil, false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	if !ok || imports[x.Name] != pkgPath {
+		return nil, false
+	}
+	return call, true
+}
+
+// constString evaluates string literals and their concatenations.
+func constString(expr ast.Expr) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return "", false
+		}
+		s, err := strconv.Unquote(e.Value)
+		return s, err == nil
+	case *ast.ParenExpr:
+		return constString(e.X)
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD {
+			return "", false
+		}
+		x, ok := constString(e.X)
+		if !ok {
+			return "", false
+		}
+		y, ok := constString(e.Y)
+		return x + y, ok
+	}
+	return "", false
+}
+
+func (s *scanner) position(pos token.Pos) token.Position {
+	p := s.fset.Position(pos)
+	if rel, err := filepath.Rel(s.root, p.Filename); err == nil {
+		p.Filename = filepath.ToSlash(rel)
+	}
+	return p
+}
+
+func (s *scanner) source(n ast.Node) string {
+	p := s.fset.Position(n.Pos())
+	end := s.fset.Position(n.End())
+	data, err := os.ReadFile(p.Filename)
+	if err != nil || end.Offset > len(data) {
+		return ""
+	}
+	return string(data[p.Offset:end.Offset])
+}
+
+func (s *scanner) findCatalogs(pkg *pkgFiles) {
+	for _, f := range pkg.files {
+		imports := fileImports(f)
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				vs := spec.(*ast.ValueSpec)
+				for i, value := range vs.Values {
+					call, ok := pkgCall(value, imports, i18nPkgPath, "File")
+					if !ok || i >= len(vs.Names) || len(call.Args) != 1 {
+						continue
+					}
+					c := &Catalog{
+						Name: pkg.importPath + "." + vs.Names[i].Name,
+						Pos:  s.position(vs.Names[i].Pos()),
+						Keys: make(map[string][]token.Position),
+					}
+					arg := call.Args[0]
+					if inner, ok := pkgCall(arg, imports, corebasePkgPath, "PkgFile"); ok && len(inner.Args) == 1 {
+						arg = inner.Args[0]
+					}
+					if rel, ok := constString(arg); ok {
+						c.Path = filepath.Join(pkg.dir, filepath.FromSlash(rel))
+					}
+					s.catalogs[c.Name] = c
+				}
+			}
+		}
+	}
+}
+
+// catalogOf resolves the receiver of a Tr call.
+func (s *scanner) catalogOf(pkg *pkgFiles, imports map[string]string, x ast.Expr) *Catalog {
+	switch x := x.(type) {
+	case *ast.Ident:
+		return s.catalogs[pkg.importPath+"."+x.Name]
+	case *ast.SelectorExpr:
+		id, ok := x.X.(*ast.Ident)
+		if !ok {
+			return nil
+		}
+		if p, ok := imports[id.Name]; ok {
+			return s.catalogs[p+"."+x.Sel.Name]
+		}
+	}
+	return nil
+}
+
+func (s *scanner) findUsages(pkg *pkgFiles) {
+	for _, f := range pkg.files {
+		imports := fileImports(f)
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) != 1 {
+				return true
+			}
+			if _, ok := pkgCall(call, imports, i18nPkgPath, "Str"); ok {
+				u := Usage{Call: s.source(call), Pos: s.position(call.Pos())}
+				if text, ok := constString(call.Args[0]); ok {
+					u.Text = text
+					s.strs = append(s.strs, u)
+				} else {
+					s.dynamic = append(s.dynamic, u)
+				}
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "Tr" {
+				return true
+			}
+			c := s.catalogOf(pkg, imports, sel.X)
+			if c == nil {
+				return true
+			}
+			if key, ok := constString(call.Args[0]); ok {
+				c.Keys[key] = append(c.Keys[key], s.position(call.Pos()))
+			} else {
+				s.dynamic = append(s.dynamic, Usage{Call: s.source(call), Pos: s.position(call.Pos())})
+			}
+			return true
+		})
+	}
+}
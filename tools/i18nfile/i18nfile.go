@@ -0,0 +1,196 @@
+// Package i18nfile reads and writes the JSON translation files loaded by
+// i18n.File, such as example/assets/i18n/app-en.json:
+//
+//	{
+//	  "messages": {
+//	    "hello": "Hello I18n"
+//	  }
+//	}
+//
+// A file declared as "i18n/app.json" has one variant per language, named
+// "i18n/app-<lang>.json".
+package i18nfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Note is translator metadata of a message. It is stored under the "notes"
+// field, which the runtime ignores.
+type Note struct {
+	Context string `json:"context,omitempty"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// File is a decoded translation file.
+type File struct {
+	Messages map[string]string
+	Notes    map[string]Note
+
+	extra map[string]json.RawMessage // unknown fields, written back as is
+}
+
+// New returns an empty file.
+func New() *File {
+	return &File{Messages: map[string]string{}, Notes: map[string]Note{}}
+}
+
+// Decode parses a translation file.
+func Decode(data []byte) (*File, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	f := New()
+	if raw, ok := fields["messages"]; ok {
+		if err := json.Unmarshal(raw, &f.Messages); err != nil {
+			return nil, fmt.Errorf("decode messages: %w", err)
+		}
+		delete(fields, "messages")
+	}
+	if raw, ok := fields["notes"]; ok {
+		if err := json.Unmarshal(raw, &f.Notes); err != nil {
+			return nil, fmt.Errorf("decode notes: %w", err)
+		}
+		delete(fields, "notes")
+	}
+	if f.Messages == nil {
+		f.Messages = map[string]string{}
+	}
+	if f.Notes == nil {
+		f.Notes = map[string]Note{}
+	}
+	if len(fields) > 0 {
+		f.extra = fields
+	}
+	return f, nil
+}
+
+// Load reads a translation file.
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return f, nil
+}
+
+func marshalIndent(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("  ", "  ")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// Encode formats the file with two space indentation and sorted keys,
+// "messages" first.
+func (f *File) Encode() ([]byte, error) {
+	type field struct {
+		name  string
+		value any
+	}
+	fields := []field{{"messages", f.Messages}}
+	notes := make(map[string]Note)
+	for k, n := range f.Notes {
+		if n != (Note{}) {
+			notes[k] = n
+		}
+	}
+	if len(notes) > 0 {
+		fields = append(fields, field{"notes", notes})
+	}
+	names := make([]string, 0, len(f.extra))
+	for name := range f.extra {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fields = append(fields, field{name, f.extra[name]})
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("{\n")
+	for i, fd := range fields {
+		value, err := marshalIndent(fd.value)
+		if err != nil {
+			return nil, err
+		}
+		name, _ := json.Marshal(fd.name)
+		fmt.Fprintf(&buf, "  %s: %s", name, value)
+		if i < len(fields)-1 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('\n')
+	}
+	buf.WriteString("}\n")
+	return buf.Bytes(), nil
+}
+
+// Save writes the file to path.
+func (f *File) Save(path string) error {
+	data, err := f.Encode()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Keys returns the sorted message keys.
+func (f *File) Keys() []string {
+	keys := make([]string, 0, len(f.Messages))
+	for k := range f.Messages {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// VariantPath returns the path of the lang variant of a file declared as base.
+func VariantPath(base, lang string) string {
+	ext := filepath.Ext(base)
+	return strings.TrimSuffix(base, ext) + "-" + lang + ext
+}
+
+// Variants returns the existing language variants of base, keyed by language.
+func Variants(base string) (map[string]string, error) {
+	ext := filepath.Ext(base)
+	prefix := strings.TrimSuffix(base, ext) + "-"
+	paths, err := filepath.Glob(globEscape(prefix) + "*" + globEscape(ext))
+	if err != nil {
+		return nil, err
+	}
+	variants := make(map[string]string)
+	for _, p := range paths {
+		lang := strings.TrimSuffix(strings.TrimPrefix(p, prefix), ext)
+		if lang != "" {
+			variants[lang] = p
+		}
+	}
+	return variants, nil
+}
+
+func globEscape(s string) string {
+	var b strings.Builder
+	for _, c := range s {
+		switch c {
+		case '*', '?', '[', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
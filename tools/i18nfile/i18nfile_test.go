@@ -0,0 +1,79 @@
+package i18nfile
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const exampleBase = "../../example/assets/i18n/app.json"
+
+func TestLoadExample(t *testing.T) {
+	variants, err := Variants(exampleBase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"en": filepath.FromSlash("../../example/assets/i18n/app-en.json"),
+		"zh": filepath.FromSlash("../../example/assets/i18n/app-zh.json"),
+	}
+	if !reflect.DeepEqual(variants, want) {
+		t.Fatalf("got variants %v, want %v", variants, want)
+	}
+	for lang, text := range map[string]string{"en": "Hello I18n", "zh": "你好 I18n"} {
+		f, err := Load(variants[lang])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := f.Messages["hello"]; got != text {
+			t.Errorf("%s: got %q, want %q", lang, got, text)
+		}
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	src := []byte(`{
+  "version": 2,
+  "messages": {"hello": "<b>Hello</b>", "files": "{count, plural, one {# file} other {# files}}"},
+  "notes": {"hello": {"context": "greeting", "comment": "shown on start"}, "files": {}}
+}`)
+	f, err := Decode(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := f.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "messages": {
+    "files": "{count, plural, one {# file} other {# files}}",
+    "hello": "<b>Hello</b>"
+  },
+  "notes": {
+    "hello": {
+      "context": "greeting",
+      "comment": "shown on start"
+    }
+  },
+  "version": 2
+}
+`
+	if string(data) != want {
+		t.Errorf("got\n%s\nwant\n%s", data, want)
+	}
+	again, err := Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	delete(f.Notes, "files")
+	if !reflect.DeepEqual(f, again) {
+		t.Errorf("round trip changed the file: %+v != %+v", f, again)
+	}
+}
+
+func TestVariantPath(t *testing.T) {
+	if got := VariantPath("i18n/app.json", "zh-Hant"); got != "i18n/app-zh-Hant.json" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,261 @@
+package msgformat
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Style is a CLDR date or time format length.
+type Style string
+
+const (
+	StyleShort  Style = "short"
+	StyleMedium Style = "medium"
+	StyleLong   Style = "long"
+	StyleFull   Style = "full"
+)
+
+func styleIndex(s Style) (int, bool) {
+	switch s {
+	case StyleShort:
+		return 0, true
+	case StyleMedium, "":
+		return 1, true
+	case StyleLong:
+		return 2, true
+	case StyleFull:
+		return 3, true
+	}
+	return 0, false
+}
+
+// dateSymbols is the CLDR gregorian calendar data of a locale.
+type dateSymbols struct {
+	datePatterns [4]string // short, medium, long, full
+	timePatterns [4]string
+	dateTimeGlue string // between date and time of a combined value
+
+	monthsAbbr   [12]string
+	monthsWide   [12]string
+	weekdaysAbbr [7]string // from Sunday
+	weekdaysWide [7]string
+	dayPeriods   [2]string // am, pm
+}
+
+var enDateSymbols = &dateSymbols{
+	datePatterns: [4]string{"M/d/yy", "MMM d, y", "MMMM d, y", "EEEE, MMMM d, y"},
+	timePatterns: [4]string{"h:mm\u202fa", "h:mm:ss\u202fa", "h:mm:ss\u202fa z", "h:mm:ss\u202fa zzzz"},
+	dateTimeGlue: ", ",
+	monthsAbbr:   [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+	monthsWide: [12]string{"January", "February", "March", "April", "May", "June",
+		"July", "August", "September", "October", "November", "December"},
+	weekdaysAbbr: [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+	weekdaysWide: [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+	dayPeriods:   [2]string{"AM", "PM"},
+}
+
+// dateData holds the calendar data compiled in. Languages not listed use English.
+var dateData = map[string]*dateSymbols{
+	"en": enDateSymbols,
+	"de": {
+		datePatterns: [4]string{"dd.MM.yy", "dd.MM.y", "d. MMMM y", "EEEE, d. MMMM y"},
+		timePatterns: [4]string{"HH:mm", "HH:mm:ss", "HH:mm:ss z", "HH:mm:ss zzzz"},
+		dateTimeGlue: ", ",
+		monthsAbbr: [12]string{"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni",
+			"Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."},
+		monthsWide: [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni",
+			"Juli", "August", "September", "Oktober", "November", "Dezember"},
+		weekdaysAbbr: [7]string{"So.", "Mo.", "Di.", "Mi.", "Do.", "Fr.", "Sa."},
+		weekdaysWide: [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		dayPeriods:   [2]string{"AM", "PM"},
+	},
+	"ru": {
+		datePatterns: [4]string{"dd.MM.y", "d MMM y 'г'.", "d MMMM y 'г'.", "EEEE, d MMMM y 'г'."},
+		timePatterns: [4]string{"HH:mm", "HH:mm:ss", "HH:mm:ss z", "HH:mm:ss zzzz"},
+		dateTimeGlue: ", ",
+		monthsAbbr: [12]string{"янв.", "февр.", "мар.", "апр.", "мая", "июн.",
+			"июл.", "авг.", "сент.", "окт.", "нояб.", "дек."},
+		monthsWide: [12]string{"января", "февраля", "марта", "апреля", "мая", "июня",
+			"июля", "августа", "сентября", "октября", "ноября", "декабря"},
+		weekdaysAbbr: [7]string{"вс", "пн", "вт", "ср", "чт", "пт", "сб"},
+		weekdaysWide: [7]string{"воскресенье", "понедельник", "вторник", "среда", "четверг", "пятница", "суббота"},
+		dayPeriods:   [2]string{"AM", "PM"},
+	},
+	"zh": {
+		datePatterns: [4]string{"y/M/d", "y年M月d日", "y年M月d日", "y年M月d日EEEE"},
+		timePatterns: [4]string{"HH:mm", "HH:mm:ss", "z HH:mm:ss", "zzzz HH:mm:ss"},
+		dateTimeGlue: " ",
+		monthsAbbr:   [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		monthsWide:   [12]string{"一月", "二月", "三月", "四月", "五月", "六月", "七月", "八月", "九月", "十月", "十一月", "十二月"},
+		weekdaysAbbr: [7]string{"周日", "周一", "周二", "周三", "周四", "周五", "周六"},
+		weekdaysWide: [7]string{"星期日", "星期一", "星期二", "星期三", "星期四", "星期五", "星期六"},
+		dayPeriods:   [2]string{"上午", "下午"},
+	},
+	"ja": {
+		datePatterns: [4]string{"y/MM/dd", "y/MM/dd", "y年M月d日", "y年M月d日EEEE"},
+		timePatterns: [4]string{"H:mm", "H:mm:ss", "H:mm:ss z", "H時mm分ss秒 zzzz"},
+		dateTimeGlue: " ",
+		monthsAbbr:   [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		monthsWide:   [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		weekdaysAbbr: [7]string{"日", "月", "火", "水", "木", "金", "土"},
+		weekdaysWide: [7]string{"日曜日", "月曜日", "火曜日", "水曜日", "木曜日", "金曜日", "土曜日"},
+		dayPeriods:   [2]string{"午前", "午後"},
+	},
+}
+
+func lookupDateSymbols(lang string) *dateSymbols {
+	if s := lookupLocale(dateData, lang); s != nil {
+		return s
+	}
+	return enDateSymbols
+}
+
+// FormatDate formats the date part of t.
+func FormatDate(lang string, t time.Time, style Style) (string, error) {
+	i, ok := styleIndex(style)
+	if !ok {
+		return "", fmt.Errorf("msgformat: unknown date style %q", style)
+	}
+	s := lookupDateSymbols(lang)
+	return s.format(t, s.datePatterns[i]), nil
+}
+
+// FormatTime formats the time part of t.
+func FormatTime(lang string, t time.Time, style Style) (string, error) {
+	i, ok := styleIndex(style)
+	if !ok {
+		return "", fmt.Errorf("msgformat: unknown time style %q", style)
+	}
+	s := lookupDateSymbols(lang)
+	return s.format(t, s.timePatterns[i]), nil
+}
+
+// FormatDateTime formats both the date and the time of t.
+func FormatDateTime(lang string, t time.Time, dateStyle, timeStyle Style) (string, error) {
+	date, err := FormatDate(lang, t, dateStyle)
+	if err != nil {
+		return "", err
+	}
+	tm, err := FormatTime(lang, t, timeStyle)
+	if err != nil {
+		return "", err
+	}
+	return date + lookupDateSymbols(lang).dateTimeGlue + tm, nil
+}
+
+// format interprets a CLDR date pattern. Only the fields used by the
+// compiled-in patterns are supported.
+func (s *dateSymbols) format(t time.Time, pattern string) string {
+	var b strings.Builder
+	for i := 0; i < len(pattern); {
+		c := pattern[i]
+		if c == '\'' {
+			i++
+			if i < len(pattern) && pattern[i] == '\'' {
+				b.WriteByte('\'')
+				i++
+				continue
+			}
+			for i < len(pattern) {
+				if pattern[i] == '\'' {
+					if i+1 < len(pattern) && pattern[i+1] == '\'' {
+						b.WriteByte('\'')
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				b.WriteByte(pattern[i])
+				i++
+			}
+			continue
+		}
+		if !isLetter(c) {
+			b.WriteByte(c)
+			i++
+			continue
+		}
+		n := 1
+		for i+n < len(pattern) && pattern[i+n] == c {
+			n++
+		}
+		i += n
+		s.field(&b, t, c, n)
+	}
+	return b.String()
+}
+
+func pad(v, width int) string {
+	s := strconv.Itoa(v)
+	for len(s) < width {
+		s = "0" + s
+	}
+	return s
+}
+
+func (s *dateSymbols) field(b *strings.Builder, t time.Time, c byte, n int) {
+	switch c {
+	case 'y':
+		if n == 2 {
+			b.WriteString(pad(t.Year()%100, 2))
+		} else {
+			b.WriteString(pad(t.Year(), n))
+		}
+	case 'M':
+		switch {
+		case n >= 4:
+			b.WriteString(s.monthsWide[t.Month()-1])
+		case n == 3:
+			b.WriteString(s.monthsAbbr[t.Month()-1])
+		default:
+			b.WriteString(pad(int(t.Month()), n))
+		}
+	case 'd':
+		b.WriteString(pad(t.Day(), n))
+	case 'E':
+		if n >= 4 {
+			b.WriteString(s.weekdaysWide[t.Weekday()])
+		} else {
+			b.WriteString(s.weekdaysAbbr[t.Weekday()])
+		}
+	case 'h':
+		h := t.Hour() % 12
+		if h == 0 {
+			h = 12
+		}
+		b.WriteString(pad(h, n))
+	case 'H':
+		b.WriteString(pad(t.Hour(), n))
+	case 'm':
+		b.WriteString(pad(t.Minute(), n))
+	case 's':
+		b.WriteString(pad(t.Second(), n))
+	case 'a':
+		b.WriteString(s.dayPeriods[t.Hour()/12])
+	case 'z':
+		name, offset := t.Zone()
+		if n < 4 && name != "" && isLetter(name[0]) {
+			b.WriteString(name)
+		} else {
+			b.WriteString(gmtOffset(offset))
+		}
+	default:
+		b.WriteString(strings.Repeat(string(c), n))
+	}
+}
+
+// gmtOffset returns the localized GMT format of a zone offset, like "GMT+08:00".
+func gmtOffset(offset int) string {
+	if offset == 0 {
+		return "GMT"
+	}
+	sign := '+'
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+	return fmt.Sprintf("GMT%c%02d:%02d", sign, offset/3600, offset%3600/60)
+}
@@ -0,0 +1,148 @@
+package msgformat
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatNumbers(t *testing.T) {
+	cases := []struct {
+		lang string
+		fn   func(lang string, v any) (string, error)
+		v    any
+		want string
+	}{
+		{"en", FormatNumber, 1234567.891, "1,234,567.891"},
+		{"en", FormatNumber, -0.0004, "0"},
+		{"en", FormatNumber, 0.0125, "0.012"},
+		{"en", FormatNumber, 0.0135, "0.014"},
+		{"en", FormatNumber, 999.9996, "1,000"},
+		{"zh", FormatNumber, 1234.5, "1,234.5"},
+		{"de", FormatNumber, -1234.5, "-1.234,5"},
+		{"ru", FormatNumber, 1234.5, "1\u00a0234,5"},
+		{"pl", FormatNumber, 1234, "1234"},
+		{"pl", FormatNumber, 12345, "12\u00a0345"},
+		{"xx", FormatNumber, 1234, "1,234"},
+		{"en", FormatPercent, 0.125, "12%"},
+		{"ru", FormatPercent, 1, "100\u00a0%"},
+		{"zh", FormatPercent, -0.5, "-50%"},
+	}
+	for _, c := range cases {
+		got, err := c.fn(c.lang, c.v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != c.want {
+			t.Errorf("%s %v: got %q, want %q", c.lang, c.v, got, c.want)
+		}
+	}
+}
+
+func TestFormatCurrency(t *testing.T) {
+	cases := []struct {
+		lang   string
+		amount any
+		code   string
+		want   string
+	}{
+		{"en", 1234.5, "USD", "$1,234.50"},
+		{"en", -3, "EUR", "-€3.00"},
+		{"en", 1234.5, "JPY", "¥1,234"},
+		{"en", 5, "CHF", "CHF\u00a05.00"},
+		{"zh", 1234.5, "CNY", "¥1,234.50"},
+		{"zh", 12, "USD", "US$12.00"},
+		{"ja", 1234, "JPY", "￥1,234"},
+		{"de", 1234.5, "EUR", "1.234,50\u00a0€"},
+		{"nl", -5, "EUR", "€\u00a0-5,00"},
+		{"ru", 99.999, "RUB", "100,00\u00a0₽"},
+	}
+	for _, c := range cases {
+		got, err := FormatCurrency(c.lang, c.amount, c.code)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != c.want {
+			t.Errorf("%s %v %s: got %q, want %q", c.lang, c.amount, c.code, got, c.want)
+		}
+	}
+}
+
+func TestFormatDateTime(t *testing.T) {
+	tm := time.Date(2024, time.January, 7, 9, 5, 3, 0, time.FixedZone("", 8*3600))
+	cases := []struct {
+		lang  string
+		style Style
+		date  string
+		time  string
+	}{
+		{"en", StyleShort, "1/7/24", "9:05\u202fAM"},
+		{"en", StyleMedium, "Jan 7, 2024", "9:05:03\u202fAM"},
+		{"en", StyleFull, "Sunday, January 7, 2024", "9:05:03\u202fAM GMT+08:00"},
+		{"zh", StyleShort, "2024/1/7", "09:05"},
+		{"zh", StyleMedium, "2024年1月7日", "09:05:03"},
+		{"zh", StyleLong, "2024年1月7日", "GMT+08:00 09:05:03"},
+		{"ja", StyleFull, "2024年1月7日日曜日", "9時05分03秒 GMT+08:00"},
+		{"de", StyleMedium, "07.01.2024", "09:05:03"},
+		{"de", StyleLong, "7. Januar 2024", "09:05:03 GMT+08:00"},
+		{"ru", StyleMedium, "7 янв. 2024 г.", "09:05:03"},
+		{"ru", StyleFull, "воскресенье, 7 января 2024 г.", "09:05:03 GMT+08:00"},
+	}
+	for _, c := range cases {
+		date, err := FormatDate(c.lang, tm, c.style)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if date != c.date {
+			t.Errorf("%s %s date: got %q, want %q", c.lang, c.style, date, c.date)
+		}
+		tmStr, err := FormatTime(c.lang, tm, c.style)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tmStr != c.time {
+			t.Errorf("%s %s time: got %q, want %q", c.lang, c.style, tmStr, c.time)
+		}
+	}
+	if _, err := FormatDate("en", tm, "tiny"); err == nil {
+		t.Error("expected error for unknown style")
+	}
+	if got, _ := FormatTime("en", tm.In(time.UTC), StyleLong); got != "1:05:03\u202fAM UTC" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestFormatRelativeTime(t *testing.T) {
+	cases := []struct {
+		lang string
+		v    any
+		unit RelativeUnit
+		want string
+	}{
+		{"en", 1, Day, "in 1 day"},
+		{"en", -3, Day, "3 days ago"},
+		{"en", 0, Second, "in 0 seconds"},
+		{"en", 1.5, Hour, "in 1.5 hours"},
+		{"de", -1, Year, "vor 1 Jahr"},
+		{"de", 2, Week, "in 2 Wochen"},
+		{"zh", -5, Minute, "5分钟前"},
+		{"zh-TW", 2, Month, "2个月后"},
+		{"ja", 3, Week, "3 週間後"},
+		{"ru", 21, Day, "через 21 день"},
+		{"ru", -3, Hour, "3 часа назад"},
+		{"ru", -5, Year, "5 лет назад"},
+		{"ru", 1.5, Minute, "через 1,5 минуты"},
+		{"xx", 2, Day, "in 2 days"},
+	}
+	for _, c := range cases {
+		got, err := FormatRelativeTime(c.lang, c.v, c.unit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != c.want {
+			t.Errorf("%s %v %s: got %q, want %q", c.lang, c.v, c.unit, got, c.want)
+		}
+	}
+	if _, err := FormatRelativeTime("en", 1, "fortnight"); err == nil {
+		t.Error("expected error for unknown unit")
+	}
+}
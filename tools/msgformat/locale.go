@@ -0,0 +1,29 @@
+package msgformat
+
+import "strings"
+
+// localeCandidates returns the lookup chain of a BCP 47 language tag, most
+// specific first: "zh_Hant_TW" yields "zh-hant-tw", "zh-hant", "zh".
+func localeCandidates(lang string) []string {
+	lang = strings.ToLower(strings.ReplaceAll(lang, "_", "-"))
+	var candidates []string
+	for lang != "" {
+		candidates = append(candidates, lang)
+		i := strings.LastIndexByte(lang, '-')
+		if i < 0 {
+			break
+		}
+		lang = lang[:i]
+	}
+	return candidates
+}
+
+func lookupLocale[T any](data map[string]T, lang string) T {
+	for _, c := range localeCandidates(lang) {
+		if v, ok := data[c]; ok {
+			return v
+		}
+	}
+	var zero T
+	return zero
+}
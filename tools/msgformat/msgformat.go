@@ -0,0 +1,235 @@
+// Package msgformat implements ICU MessageFormat with CLDR plural rules and
+// locale aware number, currency, date and relative time formatting.
+//
+// Messages look like:
+//
+//	{count, plural, =0 {no files} one {# file} other {# files}}
+//	{gender, select, female {She} male {He} other {They}} paid {price, number, currency}
+//
+// Locale data is a compiled-in subset of CLDR covering the languages the
+// example app ships (en, zh) and a few others.
+package msgformat
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Args are the named arguments of a message. Values may be strings, any Go
+// integer or float type, decimal strings, Currency or time.Time.
+type Args map[string]any
+
+// Message is a parsed message bound to a language.
+type Message struct {
+	lang  string
+	nodes []Node
+}
+
+// New parses pattern as a message of language lang.
+func New(lang, pattern string) (*Message, error) {
+	nodes, err := Parse(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return &Message{lang: lang, nodes: nodes}, nil
+}
+
+// MustNew is like New but panics on syntax errors.
+func MustNew(lang, pattern string) *Message {
+	m, err := New(lang, pattern)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Lang returns the language of the message.
+func (m *Message) Lang() string { return m.lang }
+
+// Nodes returns the parsed message.
+func (m *Message) Nodes() []Node { return m.nodes }
+
+// Format formats the message with args.
+func (m *Message) Format(args Args) (string, error) {
+	f := formatter{lang: m.lang, args: args}
+	if err := f.format(m.nodes, nil); err != nil {
+		return "", err
+	}
+	return f.b.String(), nil
+}
+
+// Format parses and formats pattern in one step.
+func Format(lang, pattern string, args Args) (string, error) {
+	m, err := New(lang, pattern)
+	if err != nil {
+		return "", err
+	}
+	return m.Format(args)
+}
+
+type formatter struct {
+	lang string
+	args Args
+	b    strings.Builder
+}
+
+func (f *formatter) arg(name string) (any, error) {
+	v, ok := f.args[name]
+	if !ok {
+		return nil, fmt.Errorf("msgformat: missing argument %q", name)
+	}
+	return v, nil
+}
+
+// format writes nodes, pound being the value of '#' in a plural case.
+func (f *formatter) format(nodes []Node, pound *decimal) error {
+	for _, n := range nodes {
+		var err error
+		switch n := n.(type) {
+		case Text:
+			f.b.WriteString(string(n))
+		case Pound:
+			if pound == nil {
+				f.b.WriteByte('#')
+			} else {
+				f.b.WriteString(lookupNumberSymbols(f.lang).formatDecimal(*pound, 3))
+			}
+		case Arg:
+			err = f.simpleArg(n)
+		case Plural:
+			err = f.plural(n)
+		case Select:
+			err = f.selectArg(n)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *formatter) simpleArg(a Arg) error {
+	v, err := f.arg(a.Name)
+	if err != nil {
+		return err
+	}
+	var s string
+	switch a.Type {
+	case "":
+		s, err = f.defaultFormat(v)
+	case "number":
+		s, err = f.number(v, a.Style)
+	case "date", "time":
+		t, ok := v.(time.Time)
+		if !ok {
+			return fmt.Errorf("msgformat: argument %q is not a time.Time", a.Name)
+		}
+		if a.Type == "date" {
+			s, err = FormatDate(f.lang, t, Style(a.Style))
+		} else {
+			s, err = FormatTime(f.lang, t, Style(a.Style))
+		}
+	}
+	if err != nil {
+		return err
+	}
+	f.b.WriteString(s)
+	return nil
+}
+
+func (f *formatter) defaultFormat(v any) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case Currency:
+		return FormatCurrency(f.lang, v.Amount, v.Code)
+	case time.Time:
+		return FormatDateTime(f.lang, v, StyleShort, StyleShort)
+	case fmt.Stringer:
+		return v.String(), nil
+	}
+	if d, ok := toDecimal(v); ok {
+		return lookupNumberSymbols(f.lang).formatDecimal(d, 3), nil
+	}
+	return fmt.Sprint(v), nil
+}
+
+func (f *formatter) number(v any, style string) (string, error) {
+	if c, ok := v.(Currency); ok {
+		switch style {
+		case "", "currency":
+			return FormatCurrency(f.lang, c.Amount, c.Code)
+		}
+		v = c.Amount
+	}
+	switch {
+	case style == "":
+		return FormatNumber(f.lang, v)
+	case style == "integer" || style == "::integer":
+		d, ok := toDecimal(v)
+		if !ok {
+			return "", errNotNumber(v)
+		}
+		return lookupNumberSymbols(f.lang).formatDecimal(d, 0), nil
+	case style == "percent" || style == "::percent":
+		return FormatPercent(f.lang, v)
+	case strings.HasPrefix(style, "::currency/"):
+		return FormatCurrency(f.lang, v, strings.TrimPrefix(style, "::currency/"))
+	case style == "currency":
+		return "", fmt.Errorf("msgformat: currency style needs a Currency argument or ::currency/CODE")
+	}
+	return "", fmt.Errorf("msgformat: unsupported number style %q", style)
+}
+
+func findCase(cases []Case, key string) *Case {
+	for i := range cases {
+		if cases[i].Key == key {
+			return &cases[i]
+		}
+	}
+	return nil
+}
+
+func (f *formatter) plural(pl Plural) error {
+	v, err := f.arg(pl.Name)
+	if err != nil {
+		return err
+	}
+	d, ok := toDecimal(v)
+	if !ok {
+		return fmt.Errorf("msgformat: argument %q: %w", pl.Name, errNotNumber(v))
+	}
+	var c *Case
+	for i := range pl.Cases {
+		key := pl.Cases[i].Key
+		if !strings.HasPrefix(key, "=") {
+			continue
+		}
+		if exact, err := strconv.ParseFloat(key[1:], 64); err == nil && exact == d.float() {
+			c = &pl.Cases[i]
+			break
+		}
+	}
+	shifted := d.sub(pl.Offset)
+	if c == nil {
+		c = findCase(pl.Cases, string(shifted.pluralCategory(f.lang, pl.Ordinal)))
+	}
+	if c == nil {
+		c = findCase(pl.Cases, string(Other))
+	}
+	return f.format(c.Nodes, &shifted)
+}
+
+func (f *formatter) selectArg(s Select) error {
+	v, err := f.arg(s.Name)
+	if err != nil {
+		return err
+	}
+	c := findCase(s.Cases, fmt.Sprint(v))
+	if c == nil {
+		c = findCase(s.Cases, string(Other))
+	}
+	return f.format(c.Nodes, nil)
+}
@@ -0,0 +1,162 @@
+package msgformat
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFormat(t *testing.T) {
+	files := "{count, plural, =0 {no files} one {# file} other {# files}}"
+	date := time.Date(2024, time.March, 5, 14, 7, 9, 0, time.UTC)
+	cases := []struct {
+		lang    string
+		pattern string
+		args    Args
+		want    string
+	}{
+		{"en", "Hello I18n", nil, "Hello I18n"},
+		{"en", "Hello {name}!", Args{"name": "Ann"}, "Hello Ann!"},
+		{"en", files, Args{"count": 0}, "no files"},
+		{"en", files, Args{"count": 1}, "1 file"},
+		{"en", files, Args{"count": 1234}, "1,234 files"},
+		{"en", files, Args{"count": "1.0"}, "1.0 files"},
+		{"en", files, Args{"count": 2.5}, "2.5 files"},
+		{"zh", "{count, plural, other {# 个文件}}", Args{"count": 1}, "1 个文件"},
+		{"ru", "{n, plural, one {# файл} few {# файла} many {# файлов} other {# файла}}", Args{"n": 21}, "21 файл"},
+		{"ru", "{n, plural, one {# файл} few {# файла} many {# файлов} other {# файла}}", Args{"n": 12}, "12 файлов"},
+		{"ru", "{n, plural, one {# файл} few {# файла} many {# файлов} other {# файла}}", Args{"n": 1.5}, "1,5 файла"},
+		{"en", "{n, plural, offset:1 =0 {nobody} =1 {{who}} one {{who} and # other} other {{who} and # others}}",
+			Args{"n": 2, "who": "Ann"}, "Ann and 1 other"},
+		{"en", "{n, plural, offset:1 =0 {nobody} =1 {{who}} one {{who} and # other} other {{who} and # others}}",
+			Args{"n": 1, "who": "Ann"}, "Ann"},
+		{"en", "{n, selectordinal, one {#st} two {#nd} few {#rd} other {#th}}", Args{"n": 22}, "22nd"},
+		{"en", "{n, selectordinal, one {#st} two {#nd} few {#rd} other {#th}}", Args{"n": 13}, "13th"},
+		{"en", "{g, select, female {She} male {He} other {They}} liked it", Args{"g": "female"}, "She liked it"},
+		{"en", "{g, select, female {She} male {He} other {They}} liked it", Args{"g": "x"}, "They liked it"},
+		{"en", "{g, select, female {{n, plural, one {her file} other {her # files}}} other {{n, plural, one {their file} other {their # files}}}}",
+			Args{"g": "female", "n": 3}, "her 3 files"},
+		{"en", "{n, plural, other {{g, select, other {# stays}}}}", Args{"n": 3, "g": "x"}, "# stays"},
+		{"en", "It''s '{literal}' and '#", nil, "It's {literal} and '#"},
+		{"en", "{n, plural, other {'#' is #}}", Args{"n": 5}, "# is 5"},
+		{"en", "{n, number}", Args{"n": 1234.5678}, "1,234.568"},
+		{"de", "{n, number}", Args{"n": 1234.5678}, "1.234,568"},
+		{"en", "{n, number, integer}", Args{"n": 2.5}, "2"},
+		{"en", "{n, number, percent}", Args{"n": 0.256}, "26%"},
+		{"de", "{n, number, percent}", Args{"n": 0.5}, "50\u00a0%"},
+		{"en", "{p, number, currency}", Args{"p": Currency{Amount: 12.5, Code: "USD"}}, "$12.50"},
+		{"de", "{p}", Args{"p": Currency{Amount: 1234.5, Code: "EUR"}}, "1.234,50\u00a0€"},
+		{"zh", "{p, number, ::currency/CNY}", Args{"p": 8}, "¥8.00"},
+		{"en", "{d, date, long}", Args{"d": date}, "March 5, 2024"},
+		{"zh", "{d, date, full}", Args{"d": date}, "2024年3月5日星期二"},
+		{"en", "{d, time, short}", Args{"d": date}, "2:07\u202fPM"},
+		{"en", "{d}", Args{"d": date}, "3/5/24, 2:07\u202fPM"},
+	}
+	for _, c := range cases {
+		got, err := Format(c.lang, c.pattern, c.args)
+		if err != nil {
+			t.Errorf("%s %q: %v", c.lang, c.pattern, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("%s %q %v: got %q, want %q", c.lang, c.pattern, c.args, got, c.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, pattern := range []string{
+		"{",
+		"}",
+		"{n",
+		"{n, plural, one {x}}",
+		"{n, plural, other {x} other {y}}",
+		"{n, select, other {x}",
+		"{n, unknown}",
+		"{n, plural, =x {a} other {b}}",
+		"{, number}",
+	} {
+		_, err := Parse(pattern)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("%q: got %v, want SyntaxError", pattern, err)
+		}
+	}
+}
+
+func TestParseCaseRaw(t *testing.T) {
+	nodes, err := Parse("{count, plural, one {# '{file}'} other {{count} files}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pl := nodes[0].(Plural)
+	if pl.Cases[0].Raw != "# '{file}'" || pl.Cases[1].Raw != "{count} files" {
+		t.Errorf("unexpected raw cases: %q %q", pl.Cases[0].Raw, pl.Cases[1].Raw)
+	}
+}
+
+func TestFormatArgErrors(t *testing.T) {
+	for _, c := range []struct {
+		pattern string
+		args    Args
+	}{
+		{"{name}", nil},
+		{"{n, plural, other {#}}", Args{"n": "many"}},
+		{"{d, date}", Args{"d": 3}},
+		{"{n, number, currency}", Args{"n": 3}},
+		{"{n, number, scientific}", Args{"n": 3}},
+	} {
+		if _, err := Format("en", c.pattern, c.args); err == nil {
+			t.Errorf("%q %v: expected error", c.pattern, c.args)
+		}
+	}
+}
+
+func TestPrint(t *testing.T) {
+	for _, pattern := range []string{
+		"Hello {name}!",
+		"It''s '{literal}' and '#",
+		"'{'''x '{}' a'|'",
+		"{n, plural, offset:1 =0 {nobody} one {# '#' {who}} other {{g, select, a {#} other {''}}}}",
+		"{n, selectordinal, one {#st} other {#th}} {d, date, short} {p, number, ::currency/EUR}",
+	} {
+		nodes, err := Parse(pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		printed := Print(nodes)
+		again, err := Parse(printed)
+		if err != nil {
+			t.Fatalf("%q printed as %q: %v", pattern, printed, err)
+		}
+		if !reflect.DeepEqual(stripRaw(nodes), stripRaw(again)) {
+			t.Errorf("%q printed as %q parses differently", pattern, printed)
+		}
+	}
+}
+
+func stripRaw(nodes []Node) []Node {
+	out := make([]Node, len(nodes))
+	for i, n := range nodes {
+		switch n := n.(type) {
+		case Plural:
+			n.Cases = stripCasesRaw(n.Cases)
+			out[i] = n
+		case Select:
+			n.Cases = stripCasesRaw(n.Cases)
+			out[i] = n
+		default:
+			out[i] = n
+		}
+	}
+	return out
+}
+
+func stripCasesRaw(cases []Case) []Case {
+	out := make([]Case, len(cases))
+	for i, c := range cases {
+		out[i] = Case{Key: c.Key, Nodes: stripRaw(c.Nodes)}
+	}
+	return out
+}
@@ -0,0 +1,326 @@
+package msgformat
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// decimal is an exact decimal number as written, so that visible fraction
+// digits ("1.50") survive for plural selection and formatting.
+type decimal struct {
+	neg  bool
+	int  string // integer digits without leading zeros, "0" for zero
+	frac string // visible fraction digits
+}
+
+func errNotNumber(v any) error {
+	return fmt.Errorf("msgformat: %v (%T) is not a number", v, v)
+}
+
+func toDecimal(v any) (decimal, bool) {
+	switch v := v.(type) {
+	case int:
+		return intDecimal(int64(v)), true
+	case int8:
+		return intDecimal(int64(v)), true
+	case int16:
+		return intDecimal(int64(v)), true
+	case int32:
+		return intDecimal(int64(v)), true
+	case int64:
+		return intDecimal(v), true
+	case uint:
+		return parseDecimal(strconv.FormatUint(uint64(v), 10))
+	case uint8:
+		return parseDecimal(strconv.FormatUint(uint64(v), 10))
+	case uint16:
+		return parseDecimal(strconv.FormatUint(uint64(v), 10))
+	case uint32:
+		return parseDecimal(strconv.FormatUint(uint64(v), 10))
+	case uint64:
+		return parseDecimal(strconv.FormatUint(v, 10))
+	case float32:
+		return floatDecimal(float64(v), 32)
+	case float64:
+		return floatDecimal(v, 64)
+	case string:
+		return parseDecimal(v)
+	}
+	return decimal{}, false
+}
+
+func intDecimal(v int64) decimal {
+	d, _ := parseDecimal(strconv.FormatInt(v, 10))
+	return d
+}
+
+func floatDecimal(v float64, bits int) (decimal, bool) {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return decimal{}, false
+	}
+	return parseDecimal(strconv.FormatFloat(v, 'f', -1, bits))
+}
+
+func parseDecimal(s string) (decimal, bool) {
+	var d decimal
+	switch {
+	case strings.HasPrefix(s, "-"):
+		d.neg = true
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+	intPart, frac, _ := strings.Cut(s, ".")
+	if intPart == "" && frac == "" {
+		return decimal{}, false
+	}
+	for _, part := range [2]string{intPart, frac} {
+		for i := 0; i < len(part); i++ {
+			if part[i] < '0' || part[i] > '9' {
+				return decimal{}, false
+			}
+		}
+	}
+	d.int = strings.TrimLeft(intPart, "0")
+	if d.int == "" {
+		d.int = "0"
+	}
+	d.frac = frac
+	if d.isZero() {
+		d.neg = false
+	}
+	return d, true
+}
+
+func (d decimal) isZero() bool {
+	return d.int == "0" && strings.Trim(d.frac, "0") == ""
+}
+
+func (d decimal) float() float64 {
+	v, _ := strconv.ParseFloat(d.int+"."+d.frac+"0", 64)
+	if d.neg {
+		v = -v
+	}
+	return v
+}
+
+// sub subtracts a plural offset, keeping the number of visible fraction digits.
+func (d decimal) sub(offset int) decimal {
+	if offset == 0 {
+		return d
+	}
+	if d.frac == "" {
+		if v, err := strconv.ParseInt(d.int, 10, 64); err == nil {
+			if d.neg {
+				v = -v
+			}
+			return intDecimal(v - int64(offset))
+		}
+	}
+	r, _ := parseDecimal(strconv.FormatFloat(d.float()-float64(offset), 'f', len(d.frac), 64))
+	return r
+}
+
+// round rounds half-even to at most maxFrac fraction digits, then trims
+// trailing zeros down to minFrac.
+func (d decimal) round(minFrac, maxFrac int) decimal {
+	if len(d.frac) > maxFrac {
+		kept, rest := d.frac[:maxFrac], d.frac[maxFrac:]
+		digits := []byte(d.int + kept)
+		up := rest[0] > '5' ||
+			(rest[0] == '5' && (strings.Trim(rest[1:], "0") != "" || (digits[len(digits)-1]-'0')%2 == 1))
+		if up {
+			i := len(digits) - 1
+			for ; i >= 0; i-- {
+				if digits[i] == '9' {
+					digits[i] = '0'
+					continue
+				}
+				digits[i]++
+				break
+			}
+			if i < 0 {
+				digits = append([]byte{'1'}, digits...)
+			}
+		}
+		n := len(digits) - maxFrac
+		r, _ := parseDecimal(string(digits[:n]) + "." + string(digits[n:]))
+		r.neg = d.neg && !r.isZero()
+		d = r
+	}
+	for len(d.frac) > minFrac && d.frac[len(d.frac)-1] == '0' {
+		d.frac = d.frac[:len(d.frac)-1]
+	}
+	for len(d.frac) < minFrac {
+		d.frac += "0"
+	}
+	return d
+}
+
+// numberSymbols is the CLDR number data of a locale.
+type numberSymbols struct {
+	decimal     string
+	group       string
+	minGrouping int // minimum digits in the highest group before grouping applies
+
+	percentSuffix  string
+	currencyPrefix bool   // currency symbol goes before the number
+	currencySpace  string // space between currency symbol and number
+}
+
+var rootNumberSymbols = &numberSymbols{
+	decimal: ".", group: ",", minGrouping: 1,
+	percentSuffix: "%", currencyPrefix: true,
+}
+
+// numberData holds the number symbols compiled in. Languages not listed use
+// the root (Latin digits, English separators) symbols.
+var numberData = map[string]*numberSymbols{
+	"en": rootNumberSymbols,
+	"zh": rootNumberSymbols,
+	"ja": rootNumberSymbols,
+	"ko": rootNumberSymbols,
+	"de": {decimal: ",", group: ".", minGrouping: 1, percentSuffix: "\u00a0%", currencySpace: "\u00a0"},
+	"nl": {decimal: ",", group: ".", minGrouping: 1, percentSuffix: "%", currencyPrefix: true, currencySpace: "\u00a0"},
+	"ru": {decimal: ",", group: "\u00a0", minGrouping: 1, percentSuffix: "\u00a0%", currencySpace: "\u00a0"},
+	"uk": {decimal: ",", group: "\u00a0", minGrouping: 1, percentSuffix: "%", currencySpace: "\u00a0"},
+	"pl": {decimal: ",", group: "\u00a0", minGrouping: 2, percentSuffix: "%", currencySpace: "\u00a0"},
+	"cs": {decimal: ",", group: "\u00a0", minGrouping: 1, percentSuffix: "\u00a0%", currencySpace: "\u00a0"},
+}
+
+func lookupNumberSymbols(lang string) *numberSymbols {
+	if s := lookupLocale(numberData, lang); s != nil {
+		return s
+	}
+	return rootNumberSymbols
+}
+
+// digits formats the absolute value of d with grouping and locale separators.
+func (s *numberSymbols) digits(d decimal) string {
+	var b strings.Builder
+	n := len(d.int)
+	group := n > 3 && n-3 >= s.minGrouping
+	for i := 0; i < n; i++ {
+		if group && i > 0 && (n-i)%3 == 0 {
+			b.WriteString(s.group)
+		}
+		b.WriteByte(d.int[i])
+	}
+	if d.frac != "" {
+		b.WriteString(s.decimal)
+		b.WriteString(d.frac)
+	}
+	return b.String()
+}
+
+func sign(d decimal) string {
+	if d.neg {
+		return "-"
+	}
+	return ""
+}
+
+// formatDecimal keeps the visible fraction digits of decimal strings such as
+// "1.50" when they need no rounding, so the output agrees with the plural
+// category.
+func (s *numberSymbols) formatDecimal(d decimal, maxFrac int) string {
+	minFrac := 0
+	if len(d.frac) <= maxFrac {
+		minFrac = len(d.frac)
+	}
+	d = d.round(minFrac, maxFrac)
+	return sign(d) + s.digits(d)
+}
+
+func (s *numberSymbols) formatPercent(d decimal) string {
+	d, _ = parseDecimal(strconv.FormatFloat(d.float()*100, 'f', -1, 64))
+	d = d.round(0, 0)
+	return sign(d) + s.digits(d) + s.percentSuffix
+}
+
+func (s *numberSymbols) formatCurrency(lang string, d decimal, code string) string {
+	code = strings.ToUpper(code)
+	fracDigits := 2
+	if currencyZeroDigits[code] {
+		fracDigits = 0
+	}
+	d = d.round(fracDigits, fracDigits)
+	symbol := currencySymbol(lang, code)
+	if !s.currencyPrefix {
+		return sign(d) + s.digits(d) + s.currencySpace + symbol
+	}
+	space := s.currencySpace
+	if space == "" && isLetter(symbol[len(symbol)-1]) {
+		space = "\u00a0"
+	}
+	if space != "" {
+		return symbol + space + sign(d) + s.digits(d)
+	}
+	return sign(d) + symbol + s.digits(d)
+}
+
+func isLetter(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+var currencyZeroDigits = map[string]bool{"JPY": true, "KRW": true}
+
+var currencySymbols = map[string]map[string]string{
+	"":   {"USD": "US$", "EUR": "€", "GBP": "£", "JPY": "JP¥", "CNY": "CN¥", "KRW": "₩", "INR": "₹"},
+	"en": {"USD": "$", "JPY": "¥"},
+	"de": {"USD": "$", "JPY": "¥"},
+	"zh": {"CNY": "¥"},
+	"ja": {"USD": "$", "JPY": "￥", "CNY": "元"},
+	"ru": {"USD": "$", "RUB": "₽"},
+	"uk": {"USD": "USD", "UAH": "₴"},
+	"pl": {"USD": "USD", "PLN": "zł"},
+	"cs": {"CZK": "Kč"},
+}
+
+func currencySymbol(lang, code string) string {
+	if sym, ok := lookupLocale(currencySymbols, lang)[code]; ok {
+		return sym
+	}
+	if sym, ok := currencySymbols[""][code]; ok {
+		return sym
+	}
+	return code
+}
+
+// Currency is a monetary argument value.
+type Currency struct {
+	Amount any // any value accepted as a number
+	Code   string
+}
+
+// FormatNumber formats v as a decimal number with at most three fraction digits.
+func FormatNumber(lang string, v any) (string, error) {
+	d, ok := toDecimal(v)
+	if !ok {
+		return "", errNotNumber(v)
+	}
+	return lookupNumberSymbols(lang).formatDecimal(d, 3), nil
+}
+
+// FormatPercent formats v as a percentage, 0.25 being "25%".
+func FormatPercent(lang string, v any) (string, error) {
+	d, ok := toDecimal(v)
+	if !ok {
+		return "", errNotNumber(v)
+	}
+	return lookupNumberSymbols(lang).formatPercent(d), nil
+}
+
+// FormatCurrency formats an amount of the ISO 4217 currency code.
+func FormatCurrency(lang string, amount any, code string) (string, error) {
+	d, ok := toDecimal(amount)
+	if !ok {
+		return "", errNotNumber(amount)
+	}
+	if code == "" {
+		return "", fmt.Errorf("msgformat: missing currency code")
+	}
+	return lookupNumberSymbols(lang).formatCurrency(lang, d, code), nil
+}
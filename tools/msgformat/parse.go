@@ -0,0 +1,322 @@
+package msgformat
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Node is an element of a parsed message.
+type Node interface {
+	node()
+}
+
+// Text is literal message text with quoting already resolved.
+type Text string
+
+// Arg is a simple argument such as {name}, {n, number, percent} or {d, date, short}.
+type Arg struct {
+	Name  string
+	Type  string // "", "number", "date" or "time"
+	Style string
+}
+
+// Pound is the '#' placeholder inside a plural case.
+type Pound struct{}
+
+// Plural is a {n, plural, ...} or {n, selectordinal, ...} argument.
+type Plural struct {
+	Name    string
+	Ordinal bool
+	Offset  int
+	Cases   []Case
+}
+
+// Select is a {name, select, ...} argument.
+type Select struct {
+	Name  string
+	Cases []Case
+}
+
+// Case is a single branch of a plural or select argument.
+type Case struct {
+	Key   string // plural category, "=N" or select keyword
+	Nodes []Node
+	Raw   string // source text between the case braces
+}
+
+func (Text) node()   {}
+func (Arg) node()    {}
+func (Pound) node()  {}
+func (Plural) node() {}
+func (Select) node() {}
+
+// SyntaxError reports an invalid message pattern.
+type SyntaxError struct {
+	Offset int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("msgformat: %s at offset %d", e.Msg, e.Offset)
+}
+
+// Parse parses an ICU MessageFormat pattern.
+func Parse(pattern string) ([]Node, error) {
+	p := parser{src: pattern}
+	return p.parseMessage(false, false)
+}
+
+type parser struct {
+	src string
+	pos int
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return &SyntaxError{Offset: p.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) parseMessage(inPlural, nested bool) ([]Node, error) {
+	var (
+		nodes []Node
+		text  strings.Builder
+	)
+	flush := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, Text(text.String()))
+			text.Reset()
+		}
+	}
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == '\'':
+			p.parseQuote(&text, inPlural)
+		case c == '{':
+			flush()
+			n, err := p.parseArg()
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, n)
+		case c == '}':
+			if !nested {
+				return nil, p.errorf("unmatched '}'")
+			}
+			flush()
+			return nodes, nil
+		case c == '#' && inPlural:
+			flush()
+			nodes = append(nodes, Pound{})
+			p.pos++
+		default:
+			text.WriteByte(c)
+			p.pos++
+		}
+	}
+	if nested {
+		return nil, p.errorf("unterminated case message")
+	}
+	flush()
+	return nodes, nil
+}
+
+// parseQuote handles apostrophes the way ICU does by default: a doubled
+// apostrophe is a literal one, an apostrophe before a syntax character starts
+// quoted text, and any other apostrophe is literal.
+func (p *parser) parseQuote(text *strings.Builder, inPlural bool) {
+	p.pos++
+	if p.pos < len(p.src) && p.src[p.pos] == '\'' {
+		text.WriteByte('\'')
+		p.pos++
+		return
+	}
+	if p.pos >= len(p.src) || !isQuotable(p.src[p.pos], inPlural) {
+		text.WriteByte('\'')
+		return
+	}
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if c == '\'' {
+			if p.pos+1 < len(p.src) && p.src[p.pos+1] == '\'' {
+				text.WriteByte('\'')
+				p.pos += 2
+				continue
+			}
+			p.pos++
+			return
+		}
+		text.WriteByte(c)
+		p.pos++
+	}
+}
+
+func isQuotable(c byte, inPlural bool) bool {
+	return c == '{' || c == '}' || c == '|' || (c == '#' && inPlural)
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case ' ', '\t', '\n', '\r':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (p *parser) eat(c byte) bool {
+	if p.pos < len(p.src) && p.src[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '-' || c == '.' ||
+		('0' <= c && c <= '9') || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') ||
+		c >= 0x80
+}
+
+func (p *parser) parseIdent() string {
+	start := p.pos
+	for p.pos < len(p.src) && isIdentByte(p.src[p.pos]) {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *parser) parseArg() (Node, error) {
+	p.pos++ // '{'
+	p.skipSpace()
+	name := p.parseIdent()
+	if name == "" {
+		return nil, p.errorf("missing argument name")
+	}
+	p.skipSpace()
+	if p.eat('}') {
+		return Arg{Name: name}, nil
+	}
+	if !p.eat(',') {
+		return nil, p.errorf("expected ',' or '}' after argument %q", name)
+	}
+	p.skipSpace()
+	typ := p.parseIdent()
+	p.skipSpace()
+	switch typ {
+	case "plural", "selectordinal":
+		if !p.eat(',') {
+			return nil, p.errorf("expected ',' after %s", typ)
+		}
+		return p.parsePlural(name, typ == "selectordinal")
+	case "select":
+		if !p.eat(',') {
+			return nil, p.errorf("expected ',' after select")
+		}
+		cases, err := p.parseCases(false)
+		if err != nil {
+			return nil, err
+		}
+		return Select{Name: name, Cases: cases}, nil
+	case "number", "date", "time":
+		if p.eat('}') {
+			return Arg{Name: name, Type: typ}, nil
+		}
+		if !p.eat(',') {
+			return nil, p.errorf("expected ',' or '}' after %s", typ)
+		}
+		start := p.pos
+		for p.pos < len(p.src) && p.src[p.pos] != '}' {
+			if p.src[p.pos] == '{' {
+				return nil, p.errorf("unexpected '{' in %s style", typ)
+			}
+			p.pos++
+		}
+		if !p.eat('}') {
+			return nil, p.errorf("unterminated argument %q", name)
+		}
+		return Arg{Name: name, Type: typ, Style: strings.TrimSpace(p.src[start : p.pos-1])}, nil
+	case "":
+		return nil, p.errorf("missing argument type")
+	default:
+		return nil, p.errorf("unknown argument type %q", typ)
+	}
+}
+
+func (p *parser) parsePlural(name string, ordinal bool) (Node, error) {
+	pl := Plural{Name: name, Ordinal: ordinal}
+	p.skipSpace()
+	if strings.HasPrefix(p.src[p.pos:], "offset:") {
+		p.pos += len("offset:")
+		p.skipSpace()
+		start := p.pos
+		for p.pos < len(p.src) && '0' <= p.src[p.pos] && p.src[p.pos] <= '9' {
+			p.pos++
+		}
+		offset, err := strconv.Atoi(p.src[start:p.pos])
+		if err != nil {
+			return nil, p.errorf("invalid plural offset")
+		}
+		pl.Offset = offset
+	}
+	cases, err := p.parseCases(true)
+	if err != nil {
+		return nil, err
+	}
+	pl.Cases = cases
+	return pl, nil
+}
+
+func (p *parser) parseCases(inPlural bool) ([]Case, error) {
+	var (
+		cases    []Case
+		hasOther bool
+	)
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.src) {
+			return nil, p.errorf("unterminated argument")
+		}
+		if p.eat('}') {
+			break
+		}
+		var key string
+		if inPlural && p.eat('=') {
+			key = p.parseIdent()
+			if _, err := strconv.ParseFloat(key, 64); err != nil {
+				return nil, p.errorf("invalid explicit value %q", key)
+			}
+			key = "=" + key
+		} else {
+			key = p.parseIdent()
+		}
+		if key == "" {
+			return nil, p.errorf("missing case keyword")
+		}
+		for _, c := range cases {
+			if c.Key == key {
+				return nil, p.errorf("duplicate case %q", key)
+			}
+		}
+		p.skipSpace()
+		if !p.eat('{') {
+			return nil, p.errorf("expected '{' after case %q", key)
+		}
+		start := p.pos
+		nodes, err := p.parseMessage(inPlural, true)
+		if err != nil {
+			return nil, err
+		}
+		cases = append(cases, Case{Key: key, Nodes: nodes, Raw: p.src[start:p.pos]})
+		p.pos++ // '}'
+		if key == "other" {
+			hasOther = true
+		}
+	}
+	if !hasOther {
+		return nil, p.errorf("missing 'other' case")
+	}
+	return cases, nil
+}
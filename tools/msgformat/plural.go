@@ -0,0 +1,248 @@
+package msgformat
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Category is a CLDR plural category.
+type Category string
+
+const (
+	Zero  Category = "zero"
+	One   Category = "one"
+	Two   Category = "two"
+	Few   Category = "few"
+	Many  Category = "many"
+	Other Category = "other"
+)
+
+// Operands are the CLDR plural operands of a number.
+type Operands struct {
+	N float64 // absolute value
+	I uint64  // integer digits
+	V int     // number of visible fraction digits
+	W int     // number of visible fraction digits without trailing zeros
+	F uint64  // visible fraction digits
+	T uint64  // visible fraction digits without trailing zeros
+}
+
+func (o *Operands) isInt() bool {
+	return o.T == 0 && o.W == 0
+}
+
+type pluralRules struct {
+	cardinal       []Category
+	ordinal        []Category
+	selectCardinal func(o *Operands) Category
+	selectOrdinal  func(o *Operands) Category
+}
+
+func otherOnly(*Operands) Category { return Other }
+
+func inRange(v, lo, hi uint64) bool { return lo <= v && v <= hi }
+
+var (
+	rulesOneOther = &pluralRules{
+		cardinal: []Category{One, Other},
+		ordinal:  []Category{Other},
+		selectCardinal: func(o *Operands) Category {
+			if o.I == 1 && o.V == 0 {
+				return One
+			}
+			return Other
+		},
+		selectOrdinal: otherOnly,
+	}
+	rulesOther = &pluralRules{
+		cardinal:       []Category{Other},
+		ordinal:        []Category{Other},
+		selectCardinal: otherOnly,
+		selectOrdinal:  otherOnly,
+	}
+	rulesEastSlavic = func(ordinal []Category, selectOrdinal func(o *Operands) Category) *pluralRules {
+		return &pluralRules{
+			cardinal: []Category{One, Few, Many, Other},
+			ordinal:  ordinal,
+			selectCardinal: func(o *Operands) Category {
+				if o.V != 0 {
+					return Other
+				}
+				i10, i100 := o.I%10, o.I%100
+				switch {
+				case i10 == 1 && i100 != 11:
+					return One
+				case inRange(i10, 2, 4) && !inRange(i100, 12, 14):
+					return Few
+				default:
+					return Many
+				}
+			},
+			selectOrdinal: selectOrdinal,
+		}
+	}
+)
+
+// pluralData holds the CLDR plural rules compiled in, keyed by language.
+// Languages not listed use the root rules, which only know "other".
+var pluralData = map[string]*pluralRules{
+	"en": {
+		cardinal:       rulesOneOther.cardinal,
+		ordinal:        []Category{One, Two, Few, Other},
+		selectCardinal: rulesOneOther.selectCardinal,
+		selectOrdinal: func(o *Operands) Category {
+			n10, n100 := o.I%10, o.I%100
+			switch {
+			case !o.isInt():
+				return Other
+			case n10 == 1 && n100 != 11:
+				return One
+			case n10 == 2 && n100 != 12:
+				return Two
+			case n10 == 3 && n100 != 13:
+				return Few
+			}
+			return Other
+		},
+	},
+	"de": rulesOneOther,
+	"nl": rulesOneOther,
+	"zh": rulesOther,
+	"ja": rulesOther,
+	"ko": rulesOther,
+	"ru": rulesEastSlavic([]Category{Other}, otherOnly),
+	"uk": rulesEastSlavic([]Category{Few, Other}, func(o *Operands) Category {
+		if o.isInt() && o.I%10 == 3 && o.I%100 != 13 {
+			return Few
+		}
+		return Other
+	}),
+	"pl": {
+		cardinal: []Category{One, Few, Many, Other},
+		ordinal:  []Category{Other},
+		selectCardinal: func(o *Operands) Category {
+			if o.V != 0 {
+				return Other
+			}
+			i10, i100 := o.I%10, o.I%100
+			switch {
+			case o.I == 1:
+				return One
+			case inRange(i10, 2, 4) && !inRange(i100, 12, 14):
+				return Few
+			default:
+				return Many
+			}
+		},
+		selectOrdinal: otherOnly,
+	},
+	"cs": {
+		cardinal: []Category{One, Few, Many, Other},
+		ordinal:  []Category{Other},
+		selectCardinal: func(o *Operands) Category {
+			switch {
+			case o.V != 0:
+				return Many
+			case o.I == 1:
+				return One
+			case inRange(o.I, 2, 4):
+				return Few
+			}
+			return Other
+		},
+		selectOrdinal: otherOnly,
+	},
+	"ar": {
+		cardinal: []Category{Zero, One, Two, Few, Many, Other},
+		ordinal:  []Category{Other},
+		selectCardinal: func(o *Operands) Category {
+			if !o.isInt() {
+				return Other
+			}
+			n100 := o.I % 100
+			switch {
+			case o.I == 0:
+				return Zero
+			case o.I == 1:
+				return One
+			case o.I == 2:
+				return Two
+			case inRange(n100, 3, 10):
+				return Few
+			case inRange(n100, 11, 99):
+				return Many
+			}
+			return Other
+		},
+		selectOrdinal: otherOnly,
+	},
+}
+
+func lookupPluralRules(lang string) *pluralRules {
+	if r := lookupLocale(pluralData, lang); r != nil {
+		return r
+	}
+	return rulesOther
+}
+
+// PluralCategories returns the cardinal (or ordinal) categories used by lang,
+// in CLDR order.
+func PluralCategories(lang string, ordinal bool) []Category {
+	r := lookupPluralRules(lang)
+	if ordinal {
+		return append([]Category(nil), r.ordinal...)
+	}
+	return append([]Category(nil), r.cardinal...)
+}
+
+// PluralCategory returns the cardinal (or ordinal) category of number v in
+// lang. v may be any Go integer or float type, or a decimal string such as
+// "1.50" whose visible fraction digits are significant.
+func PluralCategory(lang string, v any, ordinal bool) (Category, error) {
+	d, ok := toDecimal(v)
+	if !ok {
+		return "", errNotNumber(v)
+	}
+	return d.pluralCategory(lang, ordinal), nil
+}
+
+func (d decimal) pluralCategory(lang string, ordinal bool) Category {
+	o := d.operands()
+	r := lookupPluralRules(lang)
+	if ordinal {
+		return r.selectOrdinal(&o)
+	}
+	return r.selectCardinal(&o)
+}
+
+func (d decimal) operands() Operands {
+	var o Operands
+	o.I = parseDigits(d.int)
+	o.V = len(d.frac)
+	o.F = parseDigits(d.frac)
+	t := strings.TrimRight(d.frac, "0")
+	o.W = len(t)
+	o.T = parseDigits(t)
+	o.N, _ = strconv.ParseFloat(d.int+"."+d.frac+"0", 64)
+	if math.IsInf(o.N, 0) {
+		o.N = math.MaxFloat64
+	}
+	return o
+}
+
+// parseDigits parses a digit string. Values too large for uint64 keep their
+// last 18 digits offset by 10^18, which preserves every modulus used by the
+// CLDR rules while never comparing equal to a small number.
+func parseDigits(s string) uint64 {
+	if s == "" {
+		return 0
+	}
+	const maxDigits = 18
+	if len(s) <= maxDigits {
+		v, _ := strconv.ParseUint(s, 10, 64)
+		return v
+	}
+	v, _ := strconv.ParseUint(s[len(s)-maxDigits:], 10, 64)
+	return v + 1e18
+}
@@ -0,0 +1,125 @@
+package msgformat
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPluralCategory(t *testing.T) {
+	cases := []struct {
+		lang    string
+		ordinal bool
+		samples map[Category][]any
+	}{
+		{"en", false, map[Category][]any{
+			One:   {1},
+			Other: {0, 2, 11, 100, "1.0", 0.5},
+		}},
+		{"en", true, map[Category][]any{
+			One:   {1, 21, 101},
+			Two:   {2, 22, 102},
+			Few:   {3, 23, 103},
+			Other: {0, 4, 11, 12, 13, 111},
+		}},
+		{"zh", false, map[Category][]any{
+			Other: {0, 1, 2, 1.5, 1000},
+		}},
+		{"zh-Hans-CN", false, map[Category][]any{
+			Other: {1},
+		}},
+		{"de", false, map[Category][]any{
+			One:   {1},
+			Other: {0, 2, "1.0"},
+		}},
+		{"ja", false, map[Category][]any{
+			Other: {1, 2},
+		}},
+		{"ru", false, map[Category][]any{
+			One:   {1, 21, 101},
+			Few:   {2, 3, 4, 22, 104},
+			Many:  {0, 5, 11, 12, 14, 20, 111},
+			Other: {0.5, "1.0", 2.5},
+		}},
+		{"uk", true, map[Category][]any{
+			Few:   {3, 23},
+			Other: {1, 13, 4},
+		}},
+		{"pl", false, map[Category][]any{
+			One:   {1},
+			Few:   {2, 4, 22},
+			Many:  {0, 5, 11, 12, 21, 25},
+			Other: {1.5},
+		}},
+		{"cs", false, map[Category][]any{
+			One:   {1},
+			Few:   {2, 3, 4},
+			Many:  {0.5, "1.0"},
+			Other: {0, 5, 100},
+		}},
+		{"ar", false, map[Category][]any{
+			Zero:  {0},
+			One:   {1},
+			Two:   {2},
+			Few:   {3, 10, 103},
+			Many:  {11, 99, 111},
+			Other: {100, 102, 0.5},
+		}},
+		{"xx", false, map[Category][]any{
+			Other: {0, 1, 2},
+		}},
+	}
+	for _, c := range cases {
+		for want, samples := range c.samples {
+			for _, v := range samples {
+				got, err := PluralCategory(c.lang, v, c.ordinal)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got != want {
+					t.Errorf("%s ordinal=%v %v: got %s, want %s", c.lang, c.ordinal, v, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestPluralCategories(t *testing.T) {
+	cases := []struct {
+		lang    string
+		ordinal bool
+		want    []Category
+	}{
+		{"en", false, []Category{One, Other}},
+		{"en-US", true, []Category{One, Two, Few, Other}},
+		{"zh", false, []Category{Other}},
+		{"ru", false, []Category{One, Few, Many, Other}},
+		{"ar", false, []Category{Zero, One, Two, Few, Many, Other}},
+		{"xx", false, []Category{Other}},
+	}
+	for _, c := range cases {
+		if got := PluralCategories(c.lang, c.ordinal); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s ordinal=%v: got %v, want %v", c.lang, c.ordinal, got, c.want)
+		}
+	}
+}
+
+func TestOperands(t *testing.T) {
+	cases := []struct {
+		v    any
+		want Operands
+	}{
+		{1, Operands{N: 1, I: 1}},
+		{"1.50", Operands{N: 1.5, I: 1, V: 2, W: 1, F: 50, T: 5}},
+		{-2.25, Operands{N: 2.25, I: 2, V: 2, W: 2, F: 25, T: 25}},
+		{"12345678901234567891", Operands{N: 12345678901234567891, I: 1e18 + 345678901234567891}},
+	}
+	for _, c := range cases {
+		d, ok := toDecimal(c.v)
+		if !ok {
+			t.Fatalf("%v is not a number", c.v)
+		}
+		if got := d.operands(); got != c.want {
+			t.Errorf("%v: got %+v, want %+v", c.v, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package msgformat
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Print formats nodes back into a pattern that parses to the same nodes.
+func Print(nodes []Node) string {
+	var b strings.Builder
+	printNodes(&b, nodes, false)
+	return b.String()
+}
+
+func printNodes(b *strings.Builder, nodes []Node, inPlural bool) {
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case Text:
+			printText(b, string(n), inPlural)
+		case Pound:
+			b.WriteByte('#')
+		case Arg:
+			b.WriteString("{" + n.Name)
+			if n.Type != "" {
+				b.WriteString(", " + n.Type)
+				if n.Style != "" {
+					b.WriteString(", " + n.Style)
+				}
+			}
+			b.WriteByte('}')
+		case Plural:
+			typ := "plural"
+			if n.Ordinal {
+				typ = "selectordinal"
+			}
+			b.WriteString("{" + n.Name + ", " + typ + ",")
+			if n.Offset != 0 {
+				b.WriteString(" offset:" + strconv.Itoa(n.Offset))
+			}
+			printCases(b, n.Cases, true)
+		case Select:
+			b.WriteString("{" + n.Name + ", select,")
+			printCases(b, n.Cases, false)
+		}
+	}
+}
+
+func printCases(b *strings.Builder, cases []Case, inPlural bool) {
+	for _, c := range cases {
+		b.WriteString(" " + c.Key + " {")
+		printNodes(b, c.Nodes, inPlural)
+		b.WriteByte('}')
+	}
+	b.WriteByte('}')
+}
+
+// printText quotes runs of syntax characters, doubling apostrophes.
+func printText(b *strings.Builder, s string, inPlural bool) {
+	for i := 0; i < len(s); {
+		if isQuotable(s[i], inPlural) {
+			b.WriteByte('\'')
+			for ; i < len(s) && (isQuotable(s[i], inPlural) || s[i] == '\''); i++ {
+				writeTextByte(b, s[i])
+			}
+			b.WriteByte('\'')
+			continue
+		}
+		writeTextByte(b, s[i])
+		i++
+	}
+}
+
+func writeTextByte(b *strings.Builder, c byte) {
+	if c == '\'' {
+		b.WriteString("''")
+	} else {
+		b.WriteByte(c)
+	}
+}
@@ -0,0 +1,132 @@
+package msgformat
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RelativeUnit is the unit of a relative time.
+type RelativeUnit string
+
+const (
+	Second RelativeUnit = "second"
+	Minute RelativeUnit = "minute"
+	Hour   RelativeUnit = "hour"
+	Day    RelativeUnit = "day"
+	Week   RelativeUnit = "week"
+	Month  RelativeUnit = "month"
+	Year   RelativeUnit = "year"
+)
+
+// relativePatterns holds the future and past patterns of a unit by plural
+// category, "{0}" standing for the formatted number.
+type relativePatterns [2]map[Category]string
+
+func rel(future, past string) relativePatterns {
+	return relativePatterns{{Other: future}, {Other: past}}
+}
+
+func relOneOther(futureOne, futureOther, pastOne, pastOther string) relativePatterns {
+	return relativePatterns{
+		{One: futureOne, Other: futureOther},
+		{One: pastOne, Other: pastOther},
+	}
+}
+
+// relRu builds Russian patterns from the one, few and many word forms; CLDR
+// uses the few form for "other" (fractions).
+func relRu(one, few, many string) relativePatterns {
+	forms := func(format string) map[Category]string {
+		return map[Category]string{
+			One:   fmt.Sprintf(format, one),
+			Few:   fmt.Sprintf(format, few),
+			Many:  fmt.Sprintf(format, many),
+			Other: fmt.Sprintf(format, few),
+		}
+	}
+	return relativePatterns{forms("через {0} %s"), forms("{0} %s назад")}
+}
+
+var enRelativeData = map[RelativeUnit]relativePatterns{
+	Second: relOneOther("in {0} second", "in {0} seconds", "{0} second ago", "{0} seconds ago"),
+	Minute: relOneOther("in {0} minute", "in {0} minutes", "{0} minute ago", "{0} minutes ago"),
+	Hour:   relOneOther("in {0} hour", "in {0} hours", "{0} hour ago", "{0} hours ago"),
+	Day:    relOneOther("in {0} day", "in {0} days", "{0} day ago", "{0} days ago"),
+	Week:   relOneOther("in {0} week", "in {0} weeks", "{0} week ago", "{0} weeks ago"),
+	Month:  relOneOther("in {0} month", "in {0} months", "{0} month ago", "{0} months ago"),
+	Year:   relOneOther("in {0} year", "in {0} years", "{0} year ago", "{0} years ago"),
+}
+
+// relativeData holds the numeric relative time patterns compiled in.
+// Languages not listed use English.
+var relativeData = map[string]map[RelativeUnit]relativePatterns{
+	"en": enRelativeData,
+	"de": {
+		Second: relOneOther("in {0} Sekunde", "in {0} Sekunden", "vor {0} Sekunde", "vor {0} Sekunden"),
+		Minute: relOneOther("in {0} Minute", "in {0} Minuten", "vor {0} Minute", "vor {0} Minuten"),
+		Hour:   relOneOther("in {0} Stunde", "in {0} Stunden", "vor {0} Stunde", "vor {0} Stunden"),
+		Day:    relOneOther("in {0} Tag", "in {0} Tagen", "vor {0} Tag", "vor {0} Tagen"),
+		Week:   relOneOther("in {0} Woche", "in {0} Wochen", "vor {0} Woche", "vor {0} Wochen"),
+		Month:  relOneOther("in {0} Monat", "in {0} Monaten", "vor {0} Monat", "vor {0} Monaten"),
+		Year:   relOneOther("in {0} Jahr", "in {0} Jahren", "vor {0} Jahr", "vor {0} Jahren"),
+	},
+	"ru": {
+		Second: relRu("секунду", "секунды", "секунд"),
+		Minute: relRu("минуту", "минуты", "минут"),
+		Hour:   relRu("час", "часа", "часов"),
+		Day:    relRu("день", "дня", "дней"),
+		Week:   relRu("неделю", "недели", "недель"),
+		Month:  relRu("месяц", "месяца", "месяцев"),
+		Year:   relRu("год", "года", "лет"),
+	},
+	"zh": {
+		Second: rel("{0}秒钟后", "{0}秒钟前"),
+		Minute: rel("{0}分钟后", "{0}分钟前"),
+		Hour:   rel("{0}小时后", "{0}小时前"),
+		Day:    rel("{0}天后", "{0}天前"),
+		Week:   rel("{0}周后", "{0}周前"),
+		Month:  rel("{0}个月后", "{0}个月前"),
+		Year:   rel("{0}年后", "{0}年前"),
+	},
+	"ja": {
+		Second: rel("{0} 秒後", "{0} 秒前"),
+		Minute: rel("{0} 分後", "{0} 分前"),
+		Hour:   rel("{0} 時間後", "{0} 時間前"),
+		Day:    rel("{0} 日後", "{0} 日前"),
+		Week:   rel("{0} 週間後", "{0} 週間前"),
+		Month:  rel("{0} か月後", "{0} か月前"),
+		Year:   rel("{0} 年後", "{0} 年前"),
+	},
+}
+
+// FormatRelativeTime formats v units relative to now: negative values are in
+// the past, others in the future, e.g. -3 Day is "3 days ago" in English.
+func FormatRelativeTime(lang string, v any, unit RelativeUnit) (string, error) {
+	d, ok := toDecimal(v)
+	if !ok {
+		return "", errNotNumber(v)
+	}
+	data, dataLang := relativeData["en"], "en"
+	for _, c := range localeCandidates(lang) {
+		if u, ok := relativeData[c]; ok {
+			data, dataLang = u, lang
+			break
+		}
+	}
+	patterns, ok := data[unit]
+	if !ok {
+		return "", fmt.Errorf("msgformat: unknown relative time unit %q", unit)
+	}
+	past := d.neg
+	d.neg = false
+	d = d.round(0, 3)
+	forms := patterns[0]
+	if past {
+		forms = patterns[1]
+	}
+	pattern, ok := forms[d.pluralCategory(dataLang, false)]
+	if !ok {
+		pattern = forms[Other]
+	}
+	return strings.Replace(pattern, "{0}", lookupNumberSymbols(lang).digits(d), 1), nil
+}
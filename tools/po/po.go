@@ -0,0 +1,468 @@
+// Package po converts translation files to and from gettext PO files.
+//
+// Each message becomes an entry whose msgctxt is the message key, msgid the
+// source language text and msgstr the translation. Notes of the source file
+// are written as extracted comments ("#."), notes of the target file as
+// translator comments ("# "); a note context is written as a comment line
+// starting with "context: ".
+//
+// A message consisting of a single ICU plural argument is written as a
+// gettext plural entry: msgid and msgid_plural hold the source "one" and
+// "other" cases, msgstr[N] the target cases in CLDR category order, as
+// described by the Plural-Forms header. The argument name is kept in an
+// "icu-plural=<name>" flag. Other ICU messages are kept verbatim.
+package po
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/uiez/uikit/tools/i18nfile"
+	"github.com/uiez/uikit/tools/msgformat"
+)
+
+const (
+	contextPrefix = "context: "
+	pluralFlag    = "icu-plural="
+)
+
+// Document is a bilingual translation document. Untranslated entries
+// (empty msgstr) decode to empty target messages.
+type Document struct {
+	SrcLang string
+	Lang    string
+	Source  *i18nfile.File
+	Target  *i18nfile.File
+}
+
+// pluralForms are the gettext Plural-Forms expressions matching the CLDR
+// cardinal categories of msgformat, one form per category. Forms that only
+// apply to fractions are never selected for integers.
+var pluralForms = map[string]string{
+	"en": "nplurals=2; plural=(n != 1);",
+	"de": "nplurals=2; plural=(n != 1);",
+	"nl": "nplurals=2; plural=(n != 1);",
+	"zh": "nplurals=1; plural=0;",
+	"ja": "nplurals=1; plural=0;",
+	"ko": "nplurals=1; plural=0;",
+	"ru": "nplurals=4; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<12 || n%100>14) ? 1 : 2);",
+	"uk": "nplurals=4; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<12 || n%100>14) ? 1 : 2);",
+	"pl": "nplurals=4; plural=(n==1 ? 0 : n%10>=2 && n%10<=4 && (n%100<12 || n%100>14) ? 1 : 2);",
+	"cs": "nplurals=4; plural=(n==1 ? 0 : n>=2 && n<=4 ? 1 : 3);",
+	"ar": "nplurals=6; plural=(n==0 ? 0 : n==1 ? 1 : n==2 ? 2 : n%100>=3 && n%100<=10 ? 3 : n%100>=11 ? 4 : 5);",
+}
+
+// PluralForms returns the Plural-Forms header value for lang.
+func PluralForms(lang string) string {
+	base := strings.ToLower(strings.ReplaceAll(lang, "_", "-"))
+	if i := strings.IndexByte(base, '-'); i >= 0 {
+		base = base[:i]
+	}
+	if f, ok := pluralForms[base]; ok {
+		return f
+	}
+	return "nplurals=1; plural=0;"
+}
+
+// icuPlural is a message made of a single plural argument.
+type icuPlural struct {
+	arg   string
+	cases map[msgformat.Category]string // raw case text
+}
+
+// parsePlural recognizes msg as a plural over exactly the categories of lang,
+// written in canonical form so that formatPlural restores it unchanged.
+func parsePlural(msg, lang string) (icuPlural, bool) {
+	nodes, err := msgformat.Parse(msg)
+	if err != nil || len(nodes) != 1 {
+		return icuPlural{}, false
+	}
+	pl, ok := nodes[0].(msgformat.Plural)
+	if !ok || pl.Ordinal || pl.Offset != 0 {
+		return icuPlural{}, false
+	}
+	cats := msgformat.PluralCategories(lang, false)
+	if len(pl.Cases) != len(cats) {
+		return icuPlural{}, false
+	}
+	p := icuPlural{arg: pl.Name, cases: make(map[msgformat.Category]string)}
+	for i, c := range pl.Cases {
+		if c.Key != string(cats[i]) {
+			return icuPlural{}, false
+		}
+		p.cases[cats[i]] = c.Raw
+	}
+	if formatPlural(p, cats) != msg {
+		return icuPlural{}, false
+	}
+	return p, true
+}
+
+func formatPlural(p icuPlural, cats []msgformat.Category) string {
+	var b strings.Builder
+	b.WriteString("{" + p.arg + ", plural,")
+	for _, cat := range cats {
+		b.WriteString(" " + string(cat) + " {" + p.cases[cat] + "}")
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// sourcePlural returns msgid and msgid_plural of a source plural message.
+func sourcePlural(msg, lang string) (icuPlural, string, string, bool) {
+	cats := msgformat.PluralCategories(lang, false)
+	switch {
+	case len(cats) == 2 && cats[0] == msgformat.One:
+	case len(cats) == 1:
+	default:
+		return icuPlural{}, "", "", false
+	}
+	p, ok := parsePlural(msg, lang)
+	if !ok {
+		return icuPlural{}, "", "", false
+	}
+	one, ok := p.cases[msgformat.One]
+	if !ok {
+		one = p.cases[msgformat.Other]
+	}
+	return p, one, p.cases[msgformat.Other], true
+}
+
+type entry struct {
+	extracted []string
+	comments  []string
+	flags     []string
+	ctxt      *string
+	id        string
+	idPlural  *string
+	strs      map[int]*string
+	obsolete  bool
+}
+
+func commentLines(n i18nfile.Note) []string {
+	var lines []string
+	if n.Context != "" {
+		lines = append(lines, strings.Split(contextPrefix+n.Context, "\n")...)
+	}
+	if n.Comment != "" {
+		lines = append(lines, strings.Split(n.Comment, "\n")...)
+	}
+	return lines
+}
+
+func noteOf(lines []string) i18nfile.Note {
+	var (
+		n       i18nfile.Note
+		comment []string
+	)
+	for i := 0; i < len(lines); i++ {
+		if strings.HasPrefix(lines[i], contextPrefix) && n.Context == "" && len(comment) == 0 {
+			n.Context = strings.TrimPrefix(lines[i], contextPrefix)
+			continue
+		}
+		comment = append(comment, lines[i])
+	}
+	n.Comment = strings.Join(comment, "\n")
+	return n
+}
+
+// Encode writes doc as a PO file.
+func Encode(w io.Writer, doc *Document) error {
+	bw := bufio.NewWriter(w)
+	header := fmt.Sprintf("Language: %s\nMIME-Version: 1.0\nContent-Type: text/plain; charset=UTF-8\n"+
+		"Content-Transfer-Encoding: 8bit\nPlural-Forms: %s\nX-Source-Language: %s\n",
+		doc.Lang, PluralForms(doc.Lang), doc.SrcLang)
+	writeString(bw, "msgid", "")
+	writeString(bw, "msgstr", header)
+
+	keys := make(map[string]bool)
+	for k := range doc.Source.Messages {
+		keys[k] = true
+	}
+	for k := range doc.Target.Messages {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	trgCats := msgformat.PluralCategories(doc.Lang, false)
+	for _, key := range sorted {
+		src, trg := doc.Source.Messages[key], doc.Target.Messages[key]
+		bw.WriteString("\n")
+		for _, l := range commentLines(doc.Source.Notes[key]) {
+			bw.WriteString(strings.TrimRight("#. "+l, " ") + "\n")
+		}
+		for _, l := range commentLines(doc.Target.Notes[key]) {
+			bw.WriteString(strings.TrimRight("# "+l, " ") + "\n")
+		}
+
+		sp, one, other, ok := sourcePlural(src, doc.SrcLang)
+		var tp icuPlural
+		if ok && trg != "" {
+			tp, ok = parsePlural(trg, doc.Lang)
+			ok = ok && tp.arg == sp.arg
+		}
+		if ok {
+			bw.WriteString("#, " + pluralFlag + sp.arg + "\n")
+		}
+		writeString(bw, "msgctxt", key)
+		if !ok {
+			writeString(bw, "msgid", src)
+			writeString(bw, "msgstr", trg)
+			continue
+		}
+		writeString(bw, "msgid", one)
+		writeString(bw, "msgid_plural", other)
+		for i, cat := range trgCats {
+			writeString(bw, "msgstr["+strconv.Itoa(i)+"]", tp.cases[cat])
+		}
+	}
+	return bw.Flush()
+}
+
+func quote(s string) string {
+	r := strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", "\\n", "\t", "\\t", "\r", "\\r")
+	return "\"" + r.Replace(s) + "\""
+}
+
+func writeString(w *bufio.Writer, keyword, s string) {
+	if !strings.Contains(strings.TrimSuffix(s, "\n"), "\n") {
+		w.WriteString(keyword + " " + quote(s) + "\n")
+		return
+	}
+	w.WriteString(keyword + " \"\"\n")
+	for _, line := range strings.SplitAfter(s, "\n") {
+		if line != "" {
+			w.WriteString(quote(line) + "\n")
+		}
+	}
+}
+
+func unquote(s string) (string, error) {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return "", fmt.Errorf("invalid string %s", s)
+	}
+	var b strings.Builder
+	for i := 1; i < len(s)-1; i++ {
+		c := s[i]
+		if c != '\\' {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		if i >= len(s)-1 {
+			return "", fmt.Errorf("invalid escape in %s", s)
+		}
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		case '\\', '"':
+			b.WriteByte(s[i])
+		default:
+			return "", fmt.Errorf("invalid escape in %s", s)
+		}
+	}
+	return b.String(), nil
+}
+
+func newEntry() *entry {
+	return &entry{strs: make(map[int]*string)}
+}
+
+func (e *entry) started() bool {
+	return e.id != "" || e.idPlural != nil || len(e.strs) > 0
+}
+
+func parseEntries(r io.Reader) ([]*entry, error) {
+	var (
+		entries []*entry
+		cur     *entry
+		target  *string // string continued by following quoted lines
+		lineNo  int
+	)
+	start := func() *entry {
+		if cur == nil {
+			cur = newEntry()
+			entries = append(entries, cur)
+		}
+		return cur
+	}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<24)
+	for sc.Scan() {
+		lineNo++
+		line := strings.TrimSpace(sc.Text())
+		fail := func(format string, args ...any) error {
+			return fmt.Errorf("po: line %d: %s", lineNo, fmt.Sprintf(format, args...))
+		}
+		switch {
+		case line == "":
+			cur, target = nil, nil
+			continue
+		case strings.HasPrefix(line, "#"):
+			// a comment after the strings starts the next entry
+			if cur != nil && cur.started() {
+				cur = nil
+			}
+			e := start()
+			target = nil
+			switch {
+			case strings.HasPrefix(line, "#~"):
+				e.obsolete = true
+			case strings.HasPrefix(line, "#."):
+				e.extracted = append(e.extracted, strings.TrimPrefix(line[2:], " "))
+			case strings.HasPrefix(line, "#,"):
+				for _, f := range strings.Split(line[2:], ",") {
+					if f = strings.TrimSpace(f); f != "" {
+						e.flags = append(e.flags, f)
+					}
+				}
+			case strings.HasPrefix(line, "#:"), strings.HasPrefix(line, "#|"):
+			default:
+				e.comments = append(e.comments, strings.TrimPrefix(line[1:], " "))
+			}
+			continue
+		case line[0] == '"':
+			if target == nil {
+				return nil, fail("unexpected string")
+			}
+			s, err := unquote(line)
+			if err != nil {
+				return nil, fail("%v", err)
+			}
+			*target += s
+			continue
+		}
+
+		keyword, value, _ := strings.Cut(line, " ")
+		s, err := unquote(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fail("%v", err)
+		}
+		e := start()
+		switch {
+		case keyword == "msgctxt":
+			if e.ctxt != nil || e.started() {
+				cur = nil
+				e = start()
+			}
+			e.ctxt = &s
+			target = e.ctxt
+		case keyword == "msgid":
+			if e.started() {
+				cur = nil
+				e = start()
+			}
+			e.id = s
+			target = &e.id
+		case keyword == "msgid_plural":
+			e.idPlural = &s
+			target = e.idPlural
+		case keyword == "msgstr":
+			e.strs[0] = &s
+			target = &s
+		case strings.HasPrefix(keyword, "msgstr[") && strings.HasSuffix(keyword, "]"):
+			n, err := strconv.Atoi(keyword[len("msgstr[") : len(keyword)-1])
+			if err != nil || n < 0 {
+				return nil, fail("invalid keyword %s", keyword)
+			}
+			e.strs[n] = &s
+			target = &s
+		default:
+			return nil, fail("unknown keyword %s", keyword)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (e *entry) str(n int) string {
+	if p := e.strs[n]; p != nil {
+		return *p
+	}
+	return ""
+}
+
+func (e *entry) pluralArg() string {
+	for _, f := range e.flags {
+		if strings.HasPrefix(f, pluralFlag) {
+			return strings.TrimPrefix(f, pluralFlag)
+		}
+	}
+	return ""
+}
+
+// Decode reads a PO file written by Encode or by gettext tools. Entries
+// without msgctxt use msgid as key; obsolete entries are skipped.
+func Decode(r io.Reader) (*Document, error) {
+	entries, err := parseEntries(r)
+	if err != nil {
+		return nil, err
+	}
+	doc := &Document{Source: i18nfile.New(), Target: i18nfile.New()}
+	for _, e := range entries {
+		if e.ctxt == nil && e.id == "" && !e.obsolete {
+			for _, line := range strings.Split(e.str(0), "\n") {
+				name, value, _ := strings.Cut(line, ":")
+				switch strings.TrimSpace(name) {
+				case "Language":
+					doc.Lang = strings.TrimSpace(value)
+				case "X-Source-Language":
+					doc.SrcLang = strings.TrimSpace(value)
+				}
+			}
+		}
+	}
+	for _, e := range entries {
+		if e.obsolete || (e.ctxt == nil && e.id == "") {
+			continue
+		}
+		key := e.id
+		if e.ctxt != nil {
+			key = *e.ctxt
+		}
+		src, trg := e.id, e.str(0)
+		if arg := e.pluralArg(); arg != "" && e.idPlural != nil {
+			srcCats := msgformat.PluralCategories(doc.SrcLang, false)
+			sp := icuPlural{arg: arg, cases: map[msgformat.Category]string{msgformat.Other: *e.idPlural}}
+			if len(srcCats) > 1 {
+				sp.cases[msgformat.One] = e.id
+			}
+			src = formatPlural(sp, srcCats)
+
+			trgCats := msgformat.PluralCategories(doc.Lang, false)
+			tp := icuPlural{arg: arg, cases: make(map[msgformat.Category]string)}
+			translated := false
+			for i, cat := range trgCats {
+				tp.cases[cat] = e.str(i)
+				translated = translated || e.str(i) != ""
+			}
+			trg = ""
+			if translated {
+				trg = formatPlural(tp, trgCats)
+			}
+		}
+		doc.Source.Messages[key] = src
+		doc.Target.Messages[key] = trg
+		if len(e.extracted) > 0 {
+			doc.Source.Notes[key] = noteOf(e.extracted)
+		}
+		if len(e.comments) > 0 {
+			doc.Target.Notes[key] = noteOf(e.comments)
+		}
+	}
+	return doc, nil
+}
@@ -0,0 +1,155 @@
+package po
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/uiez/uikit/tools/i18nfile"
+)
+
+func loadExample(t *testing.T) (en, zh *i18nfile.File) {
+	var err error
+	if en, err = i18nfile.Load("../../example/assets/i18n/app-en.json"); err != nil {
+		t.Fatal(err)
+	}
+	if zh, err = i18nfile.Load("../../example/assets/i18n/app-zh.json"); err != nil {
+		t.Fatal(err)
+	}
+	en.Messages["files"] = "{count, plural, one {# file} other {# files}}"
+	zh.Messages["files"] = "{count, plural, other {# 个文件}}"
+	en.Messages["open"] = "Open \"{name}\"\nin a new window"
+	zh.Messages["open"] = ""
+	en.Notes["hello"] = i18nfile.Note{Context: "window title", Comment: "Shown on start.\nKeep it short."}
+	zh.Notes["hello"] = i18nfile.Note{Comment: "reviewed"}
+	en.Notes["files"] = i18nfile.Note{Context: "status bar"}
+	return en, zh
+}
+
+func TestRoundTrip(t *testing.T) {
+	en, zh := loadExample(t)
+	var buf bytes.Buffer
+	if err := Encode(&buf, &Document{SrcLang: "en", Lang: "zh", Source: en, Target: zh}); err != nil {
+		t.Fatal(err)
+	}
+	want := `#. context: status bar
+#, icu-plural=count
+msgctxt "files"
+msgid "# file"
+msgid_plural "# files"
+msgstr[0] "# 个文件"
+
+#. context: window title
+#. Shown on start.
+#. Keep it short.
+# reviewed
+msgctxt "hello"
+msgid "Hello I18n"
+msgstr "你好 I18n"
+
+msgctxt "open"
+msgid ""
+"Open \"{name}\"\n"
+"in a new window"
+msgstr ""
+`
+	if !strings.HasSuffix(buf.String(), want) {
+		t.Errorf("got\n%s\nwant suffix\n%s", buf.String(), want)
+	}
+	if !strings.Contains(buf.String(), `"Plural-Forms: nplurals=1; plural=0;\n"`) {
+		t.Errorf("missing Plural-Forms header in\n%s", buf.String())
+	}
+
+	doc, err := Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.SrcLang != "en" || doc.Lang != "zh" {
+		t.Errorf("got languages %q, %q", doc.SrcLang, doc.Lang)
+	}
+	if !reflect.DeepEqual(doc.Source, en) {
+		t.Errorf("source changed: %+v != %+v", doc.Source, en)
+	}
+	if !reflect.DeepEqual(doc.Target, zh) {
+		t.Errorf("target changed: %+v != %+v", doc.Target, zh)
+	}
+}
+
+func TestPluralForms(t *testing.T) {
+	en, _ := loadExample(t)
+	ru := i18nfile.New()
+	ru.Messages["files"] = "{count, plural, one {# файл} few {# файла} many {# файлов} other {# файла}}"
+	var buf bytes.Buffer
+	if err := Encode(&buf, &Document{SrcLang: "en", Lang: "ru", Source: en, Target: ru}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "msgstr[2] \"# файлов\"\nmsgstr[3] \"# файла\"\n") {
+		t.Errorf("unexpected plural entry in\n%s", buf.String())
+	}
+	doc, err := Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := doc.Target.Messages["files"]; got != ru.Messages["files"] {
+		t.Errorf("got %q", got)
+	}
+	// hello has no translation
+	if got, ok := doc.Target.Messages["hello"]; !ok || got != "" {
+		t.Errorf("got %q, %v", got, ok)
+	}
+}
+
+func TestPluralKeptVerbatim(t *testing.T) {
+	src, trg := i18nfile.New(), i18nfile.New()
+	src.Messages["n"] = "{n, plural, =0 {none} one {# item} other {# items}}"
+	trg.Messages["n"] = "{n, plural, =0 {keine} one {# Element} other {# Elemente}}"
+	var buf bytes.Buffer
+	if err := Encode(&buf, &Document{SrcLang: "en", Lang: "de", Source: src, Target: trg}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "msgid_plural") {
+		t.Errorf("explicit plural case converted to gettext plural:\n%s", buf.String())
+	}
+	doc, err := Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(doc.Source, src) || !reflect.DeepEqual(doc.Target, trg) {
+		t.Errorf("unexpected document %+v %+v", doc.Source, doc.Target)
+	}
+}
+
+func TestDecodeGettext(t *testing.T) {
+	doc, err := Decode(strings.NewReader(`# header
+msgid ""
+msgstr "Language: de\n"
+
+#: main.go:12
+#, fuzzy
+msgid "Open"
+msgstr "Öffnen"
+
+#~ msgid "Old"
+#~ msgstr "Alt"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Lang != "de" || len(doc.Target.Messages) != 1 || doc.Target.Messages["Open"] != "Öffnen" {
+		t.Errorf("unexpected document %+v", doc.Target)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, s := range []string{
+		"msgid \"a\nmsgstr \"\"\n",
+		"msgid \"a\"\nmsgfoo \"\"\n",
+		"\"dangling\"\n",
+		"msgid \"\\q\"\n",
+	} {
+		if _, err := Decode(strings.NewReader(s)); err == nil {
+			t.Errorf("%q: no error", s)
+		}
+	}
+}
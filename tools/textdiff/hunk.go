@@ -0,0 +1,169 @@
+package textdiff
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Hunk is a group of changes with up to context equal lines around them.
+// The lines between two hunks are the unchanged regions a view collapses.
+type Hunk struct {
+	A0, A1 int
+	B0, B1 int
+	Spans  []Span
+}
+
+// Hunks groups spans into hunks with context equal lines before and after
+// each change. Changes separated by at most 2*context equal lines share a
+// hunk.
+func Hunks(spans []Span, context int) []Hunk {
+	var (
+		hunks []Hunk
+		cur   []Span
+	)
+	flush := func() {
+		if len(cur) == 0 {
+			return
+		}
+		first, last := cur[0], cur[len(cur)-1]
+		hunks = append(hunks, Hunk{first.A0, last.A1, first.B0, last.B1, cur})
+		cur = nil
+	}
+	for i, s := range spans {
+		if s.Op != Equal {
+			if len(cur) == 0 && i > 0 {
+				p := spans[i-1]
+				n := p.A1 - p.A0
+				if n > context {
+					n = context
+				}
+				if n > 0 {
+					cur = append(cur, Span{Equal, p.A1 - n, p.A1, p.B1 - n, p.B1})
+				}
+			}
+			cur = append(cur, s)
+			continue
+		}
+		if len(cur) == 0 {
+			continue
+		}
+		n := s.A1 - s.A0
+		if i < len(spans)-1 && n <= 2*context {
+			cur = append(cur, s)
+			continue
+		}
+		if n > context {
+			n = context
+		}
+		if n > 0 {
+			cur = append(cur, Span{Equal, s.A0, s.A0 + n, s.B0, s.B0 + n})
+		}
+		flush()
+	}
+	flush()
+	return hunks
+}
+
+func unifiedRange(start, n int) string {
+	switch n {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprint(start + 1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, n)
+}
+
+// WriteUnified writes the hunks of the line diff of a and b in unified diff
+// format.
+func WriteUnified(w io.Writer, nameA, nameB string, a, b []string, hunks []Hunk) error {
+	if len(hunks) == 0 {
+		return nil
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "--- %s\n+++ %s\n", nameA, nameB)
+	line := func(prefix byte, s string) {
+		bw.WriteByte(prefix)
+		bw.WriteString(s)
+		if !strings.HasSuffix(s, "\n") {
+			bw.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+	for _, h := range hunks {
+		fmt.Fprintf(bw, "@@ -%s +%s @@\n", unifiedRange(h.A0, h.A1-h.A0), unifiedRange(h.B0, h.B1-h.B0))
+		for _, s := range h.Spans {
+			switch s.Op {
+			case Equal:
+				for _, l := range a[s.A0:s.A1] {
+					line(' ', l)
+				}
+			case Delete:
+				for _, l := range a[s.A0:s.A1] {
+					line('-', l)
+				}
+			case Insert:
+				for _, l := range b[s.B0:s.B1] {
+					line('+', l)
+				}
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+// Unified returns the unified patience diff of a and b with 3 lines of
+// context, or "" if they are equal.
+func Unified(nameA, nameB, a, b string) string {
+	la, lb := Lines(a), Lines(b)
+	var sb strings.Builder
+	WriteUnified(&sb, nameA, nameB, la, lb, Hunks(Patience(la, lb), 3))
+	return sb.String()
+}
+
+// Row is a line of a side-by-side view. A and B are line indexes, -1 for the
+// empty side of a Delete or Insert row.
+type Row struct {
+	Op   Op
+	A, B int
+}
+
+// SideBySide returns the rows of a hunk. Deleted lines followed by inserted
+// lines are paired into Change rows, for which Inline gives the intra-line
+// difference.
+func SideBySide(h Hunk) []Row {
+	var rows []Row
+	spans := h.Spans
+	for i := 0; i < len(spans); i++ {
+		s := spans[i]
+		switch s.Op {
+		case Equal:
+			for k := 0; k < s.A1-s.A0; k++ {
+				rows = append(rows, Row{Equal, s.A0 + k, s.B0 + k})
+			}
+		case Insert:
+			for j := s.B0; j < s.B1; j++ {
+				rows = append(rows, Row{Insert, -1, j})
+			}
+		case Delete:
+			var ins Span
+			if i+1 < len(spans) && spans[i+1].Op == Insert {
+				ins = spans[i+1]
+				i++
+			}
+			na, nb := s.A1-s.A0, ins.B1-ins.B0
+			for k := 0; k < na || k < nb; k++ {
+				switch {
+				case k < na && k < nb:
+					rows = append(rows, Row{Change, s.A0 + k, ins.B0 + k})
+				case k < na:
+					rows = append(rows, Row{Delete, s.A0 + k, -1})
+				default:
+					rows = append(rows, Row{Insert, -1, ins.B0 + k})
+				}
+			}
+		}
+	}
+	return rows
+}
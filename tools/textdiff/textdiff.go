@@ -0,0 +1,347 @@
+// Package textdiff computes line and intra-line differences between texts.
+//
+// Myers finds a minimal edit script; Patience anchors on lines that occur
+// once in both texts, which keeps moved blocks and braces of config files
+// aligned the way a reader expects, and falls back to Myers between anchors.
+// Hunks groups the changes with surrounding context so unchanged regions can
+// be collapsed, Unified renders them as a unified diff and SideBySide pairs
+// the lines of a hunk into rows for a two column view.
+package textdiff
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Op is the kind of a span or row.
+type Op int8
+
+const (
+	Equal Op = iota
+	Delete
+	Insert
+	// Change is a deleted line paired with an inserted line, only used by
+	// rows.
+	Change
+)
+
+func (op Op) String() string {
+	switch op {
+	case Equal:
+		return "equal"
+	case Delete:
+		return "delete"
+	case Insert:
+		return "insert"
+	case Change:
+		return "change"
+	}
+	return "unknown"
+}
+
+// Span is a range of a and the range of b it corresponds to: equal ranges
+// have the same length, a Delete span has an empty b range and an Insert span
+// an empty a range. Spans cover both inputs in order; a change is a Delete
+// span followed by an Insert span.
+type Span struct {
+	Op     Op
+	A0, A1 int
+	B0, B1 int
+}
+
+// match is a pair of equal elements a[0] and b[1].
+type match [2]int
+
+// Myers returns the spans of a minimal edit script transforming a into b.
+func Myers[T comparable](a, b []T) []Span {
+	var ms []match
+	myers(a, b, 0, 0, &ms)
+	return spansOf(ms, len(a), len(b))
+}
+
+// Patience returns the spans of the patience diff of a and b.
+func Patience[T comparable](a, b []T) []Span {
+	var ms []match
+	patience(a, b, 0, 0, &ms)
+	return spansOf(ms, len(a), len(b))
+}
+
+// trim appends the matches of the common prefix of a and b and returns the
+// length of the common prefix and suffix.
+func trim[T comparable](a, b []T, a0, b0 int, ms *[]match) (prefix, suffix int) {
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		*ms = append(*ms, match{a0 + prefix, b0 + prefix})
+		prefix++
+	}
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	return prefix, suffix
+}
+
+func appendSuffix(ms *[]match, a1, b1, suffix int) {
+	for i := suffix; i > 0; i-- {
+		*ms = append(*ms, match{a1 - i, b1 - i})
+	}
+}
+
+// myers appends the matches of a minimal edit script of a and b, offset by
+// a0 and b0, in increasing order.
+func myers[T comparable](a, b []T, a0, b0 int, ms *[]match) {
+	prefix, suffix := trim(a, b, a0, b0, ms)
+	defer appendSuffix(ms, a0+len(a), b0+len(b), suffix)
+	a, b = a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	a0, b0 = a0+prefix, b0+prefix
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 {
+		return
+	}
+
+	// trace[d][k+d] is the furthest x reached on diagonal k with d edits.
+	var trace [][]int
+	v := make([]int, 2*(n+m)+3)
+	off := n + m + 1
+search:
+	for d := 0; d <= n+m; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[off+k-1] < v[off+k+1]) {
+				x = v[off+k+1]
+			} else {
+				x = v[off+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[off+k] = x
+			if x >= n && y >= m {
+				trace = append(trace, append([]int(nil), v[off-d:off+d+1]...))
+				break search
+			}
+		}
+		trace = append(trace, append([]int(nil), v[off-d:off+d+1]...))
+	}
+
+	// walk back from the end, collecting the snakes in reverse
+	var rev []match
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		prev := trace[d-1]
+		k := x - y
+		var pk int
+		if k == -d || (k != d && prev[k-1+d-1] < prev[k+1+d-1]) {
+			pk = k + 1
+		} else {
+			pk = k - 1
+		}
+		px := prev[pk+d-1]
+		py := px - pk
+		for x > px && y > py {
+			x--
+			y--
+			rev = append(rev, match{x, y})
+		}
+		x, y = px, py
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		rev = append(rev, match{x, y})
+	}
+	for i := len(rev) - 1; i >= 0; i-- {
+		*ms = append(*ms, match{a0 + rev[i][0], b0 + rev[i][1]})
+	}
+}
+
+// patience appends the matches of the patience diff of a and b.
+func patience[T comparable](a, b []T, a0, b0 int, ms *[]match) {
+	prefix, suffix := trim(a, b, a0, b0, ms)
+	defer appendSuffix(ms, a0+len(a), b0+len(b), suffix)
+	a, b = a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	a0, b0 = a0+prefix, b0+prefix
+	if len(a) == 0 || len(b) == 0 {
+		return
+	}
+
+	type occurrence struct{ countA, countB, indexA, indexB int }
+	occ := make(map[T]*occurrence)
+	for i, e := range a {
+		o := occ[e]
+		if o == nil {
+			o = &occurrence{}
+			occ[e] = o
+		}
+		o.countA++
+		o.indexA = i
+	}
+	for j, e := range b {
+		if o := occ[e]; o != nil {
+			o.countB++
+			o.indexB = j
+		}
+	}
+	// unique common elements in the order of a
+	var uniq []match
+	for _, e := range a {
+		if o := occ[e]; o.countA == 1 && o.countB == 1 {
+			uniq = append(uniq, match{o.indexA, o.indexB})
+		}
+	}
+	anchors := longestIncreasing(uniq)
+	if len(anchors) == 0 {
+		myers(a, b, a0, b0, ms)
+		return
+	}
+	i, j := 0, 0
+	for _, an := range anchors {
+		patience(a[i:an[0]], b[j:an[1]], a0+i, b0+j, ms)
+		*ms = append(*ms, match{a0 + an[0], b0 + an[1]})
+		i, j = an[0]+1, an[1]+1
+	}
+	patience(a[i:], b[j:], a0+i, b0+j, ms)
+}
+
+// longestIncreasing returns the longest subsequence of ms, which is sorted
+// by a, that is also increasing in b, using patience sorting.
+func longestIncreasing(ms []match) []match {
+	var (
+		tops []int // index in ms of the top card of each pile
+		back = make([]int, len(ms))
+	)
+	for i, m := range ms {
+		lo, hi := 0, len(tops)
+		for lo < hi {
+			mid := (lo + hi) / 2
+			if ms[tops[mid]][1] < m[1] {
+				lo = mid + 1
+			} else {
+				hi = mid
+			}
+		}
+		back[i] = -1
+		if lo > 0 {
+			back[i] = tops[lo-1]
+		}
+		if lo == len(tops) {
+			tops = append(tops, i)
+		} else {
+			tops[lo] = i
+		}
+	}
+	if len(tops) == 0 {
+		return nil
+	}
+	seq := make([]match, len(tops))
+	for i, k := len(tops)-1, tops[len(tops)-1]; k >= 0; i, k = i-1, back[k] {
+		seq[i] = ms[k]
+	}
+	return seq
+}
+
+// spansOf converts increasing matches of inputs of length n and m to spans.
+func spansOf(ms []match, n, m int) []Span {
+	var spans []Span
+	add := func(op Op, a0, a1, b0, b1 int) {
+		if a0 == a1 && b0 == b1 {
+			return
+		}
+		if l := len(spans) - 1; l >= 0 && spans[l].Op == op && spans[l].A1 == a0 && spans[l].B1 == b0 {
+			spans[l].A1, spans[l].B1 = a1, b1
+			return
+		}
+		spans = append(spans, Span{op, a0, a1, b0, b1})
+	}
+	i, j := 0, 0
+	for _, mt := range append(ms, match{n, m}) {
+		add(Delete, i, mt[0], j, j)
+		add(Insert, mt[0], mt[0], j, mt[1])
+		if mt[0] < n {
+			add(Equal, mt[0], mt[0]+1, mt[1], mt[1]+1)
+		}
+		i, j = mt[0]+1, mt[1]+1
+	}
+	return spans
+}
+
+// Lines splits s into lines, each ending with "\n" except possibly the last.
+func Lines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// tokens splits s into words, runs of spaces and single other characters,
+// returning the byte offset of each token and len(s).
+func tokens(s string) []int {
+	class := func(r rune) int {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			return 1
+		case unicode.IsSpace(r):
+			return 2
+		}
+		return 0
+	}
+	var offs []int
+	prev := -1
+	for i, r := range s {
+		c := class(r)
+		if c == 0 || c != prev {
+			offs = append(offs, i)
+		}
+		prev = c
+	}
+	return append(offs, len(s))
+}
+
+// Inline returns the spans of the intra-line difference of a and b, in byte
+// offsets. Words are compared as a whole, and spaces between two changes are
+// made part of the change so that it reads as one phrase.
+func Inline(a, b string) []Span {
+	ta, tb := tokens(a), tokens(b)
+	wa, wb := make([]string, len(ta)-1), make([]string, len(tb)-1)
+	for i := range wa {
+		wa[i] = a[ta[i]:ta[i+1]]
+	}
+	for i := range wb {
+		wb[i] = b[tb[i]:tb[i+1]]
+	}
+	spans := Myers(wa, wb)
+	for i := range spans {
+		s := &spans[i]
+		s.A0, s.A1, s.B0, s.B1 = ta[s.A0], ta[s.A1], tb[s.B0], tb[s.B1]
+	}
+
+	changed := func(i int) bool {
+		if spans[i].Op != Equal {
+			return true
+		}
+		return i > 0 && i < len(spans)-1 && spans[i-1].Op != Equal && spans[i+1].Op != Equal &&
+			strings.TrimSpace(a[spans[i].A0:spans[i].A1]) == ""
+	}
+	var out []Span
+	for i := 0; i < len(spans); {
+		if !changed(i) {
+			out = append(out, spans[i])
+			i++
+			continue
+		}
+		j := i
+		for j < len(spans) && changed(j) {
+			j++
+		}
+		a0, a1, b0, b1 := spans[i].A0, spans[j-1].A1, spans[i].B0, spans[j-1].B1
+		if a0 < a1 {
+			out = append(out, Span{Delete, a0, a1, b0, b0})
+		}
+		if b0 < b1 {
+			out = append(out, Span{Insert, a1, a1, b0, b1})
+		}
+		i = j
+	}
+	return out
+}
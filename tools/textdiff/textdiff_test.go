@@ -0,0 +1,293 @@
+package textdiff
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// apply checks that spans cover a and b and returns b rebuilt from them.
+func apply(t *testing.T, a, b []byte, spans []Span) string {
+	t.Helper()
+	var out []byte
+	i, j := 0, 0
+	for _, s := range spans {
+		if s.A0 != i || s.B0 != j {
+			t.Fatalf("%q -> %q: span %+v does not start at %d, %d", a, b, s, i, j)
+		}
+		switch s.Op {
+		case Equal:
+			if string(a[s.A0:s.A1]) != string(b[s.B0:s.B1]) {
+				t.Fatalf("%q -> %q: unequal span %+v", a, b, s)
+			}
+			out = append(out, a[s.A0:s.A1]...)
+		case Delete:
+			if s.B0 != s.B1 || s.A0 == s.A1 {
+				t.Fatalf("%q -> %q: bad delete %+v", a, b, s)
+			}
+		case Insert:
+			if s.A0 != s.A1 || s.B0 == s.B1 {
+				t.Fatalf("%q -> %q: bad insert %+v", a, b, s)
+			}
+			out = append(out, b[s.B0:s.B1]...)
+		}
+		i, j = s.A1, s.B1
+	}
+	if i != len(a) || j != len(b) {
+		t.Fatalf("%q -> %q: spans end at %d, %d", a, b, i, j)
+	}
+	return string(out)
+}
+
+func lcs(a, b []byte) int {
+	dp := make([][]int, len(a)+1)
+	for i := range dp {
+		dp[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				dp[i][j] = dp[i+1][j+1] + 1
+			case dp[i+1][j] > dp[i][j+1]:
+				dp[i][j] = dp[i+1][j]
+			default:
+				dp[i][j] = dp[i][j+1]
+			}
+		}
+	}
+	return dp[0][0]
+}
+
+func equalCount(spans []Span) int {
+	n := 0
+	for _, s := range spans {
+		if s.Op == Equal {
+			n += s.A1 - s.A0
+		}
+	}
+	return n
+}
+
+func TestRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	gen := func() []byte {
+		s := make([]byte, r.Intn(12))
+		for i := range s {
+			s[i] = "abcd"[r.Intn(4)]
+		}
+		return s
+	}
+	for n := 0; n < 2000; n++ {
+		a, b := gen(), gen()
+		spans := Myers(a, b)
+		if got := apply(t, a, b, spans); got != string(b) {
+			t.Fatalf("%q -> %q: myers rebuilt %q", a, b, got)
+		}
+		if got, want := equalCount(spans), lcs(a, b); got != want {
+			t.Fatalf("%q -> %q: myers kept %d elements, want %d: %+v", a, b, got, want, spans)
+		}
+		if got := apply(t, a, b, Patience(a, b)); got != string(b) {
+			t.Fatalf("%q -> %q: patience rebuilt %q", a, b, got)
+		}
+	}
+}
+
+const (
+	frobA = `#include <stdio.h>
+
+// Frobs foo heartily
+int frobnitz(int foo)
+{
+    int i;
+    for(i = 0; i < 10; i++)
+    {
+        printf("Your answer is: ");
+        printf("%d\n", foo);
+    }
+}
+
+int fact(int n)
+{
+    if(n > 1)
+    {
+        return fact(n-1) * n;
+    }
+    return 1;
+}
+
+int main(int argc, char **argv)
+{
+    frobnitz(fact(10));
+}
+`
+	frobB = `#include <stdio.h>
+
+int fib(int n)
+{
+    if(n > 2)
+    {
+        return fib(n-1) + fib(n-2);
+    }
+    return 1;
+}
+
+// Frobs foo heartily
+int frobnitz(int foo)
+{
+    int i;
+    for(i = 0; i < 10; i++)
+    {
+        printf("%d\n", foo);
+    }
+}
+
+int main(int argc, char **argv)
+{
+    frobnitz(fib(10));
+}
+`
+)
+
+func TestPatience(t *testing.T) {
+	// the example patience diff was made for: anchoring on unique lines
+	// keeps each function whole instead of pairing up their braces
+	want := `--- a
++++ b
+@@ -1,26 +1,25 @@
+ #include <stdio.h>
+ 
++int fib(int n)
++{
++    if(n > 2)
++    {
++        return fib(n-1) + fib(n-2);
++    }
++    return 1;
++}
++
+ // Frobs foo heartily
+ int frobnitz(int foo)
+ {
+     int i;
+     for(i = 0; i < 10; i++)
+     {
+-        printf("Your answer is: ");
+         printf("%d\n", foo);
+     }
+ }
+ 
+-int fact(int n)
+-{
+-    if(n > 1)
+-    {
+-        return fact(n-1) * n;
+-    }
+-    return 1;
+-}
+-
+ int main(int argc, char **argv)
+ {
+-    frobnitz(fact(10));
++    frobnitz(fib(10));
+ }
+`
+	if got := Unified("a", "b", frobA, frobB); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+	a, b := Lines(frobA), Lines(frobB)
+	if m, p := equalCount(Myers(a, b)), equalCount(Patience(a, b)); m < p {
+		t.Errorf("myers kept %d lines, patience %d", m, p)
+	}
+}
+
+func TestHunks(t *testing.T) {
+	var a, b []string
+	for i := 0; i < 30; i++ {
+		a = append(a, string(rune('a'+i%26))+"\n")
+	}
+	b = append(b, a...)
+	b[2] = "X\n"
+	b[8] = "Y\n"
+	b = append(b[:20], b[21:]...)
+	b[len(b)-1] = "end"
+
+	hunks := Hunks(Myers(a, b), 3)
+	var got []string
+	for _, h := range hunks {
+		got = append(got, unifiedRange(h.A0, h.A1-h.A0)+" "+unifiedRange(h.B0, h.B1-h.B0))
+	}
+	// changes 6 lines apart share a hunk
+	want := []string{"1,12 1,12", "18,7 18,6", "27,4 26,4"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got hunks %q, want %q", got, want)
+	}
+
+	var sb strings.Builder
+	if err := WriteUnified(&sb, "a", "b", a, b, hunks[2:]); err != nil {
+		t.Fatal(err)
+	}
+	wantDiff := `--- a
++++ b
+@@ -27,4 +26,4 @@
+ a
+ b
+ c
+-d
++end
+\ No newline at end of file
+`
+	if sb.String() != wantDiff {
+		t.Errorf("got\n%s\nwant\n%s", sb.String(), wantDiff)
+	}
+	if got := Unified("a", "b", "x\n", "x\n"); got != "" {
+		t.Errorf("got diff of equal texts %q", got)
+	}
+}
+
+func TestSideBySide(t *testing.T) {
+	a := Lines("keep\nold 1\nold 2\nold 3\nkeep\n")
+	b := Lines("keep\nnew 1\nkeep\nadded\n")
+	rows := SideBySide(Hunks(Myers(a, b), 1)[0])
+	want := []Row{
+		{Equal, 0, 0},
+		{Change, 1, 1},
+		{Delete, 2, -1},
+		{Delete, 3, -1},
+		{Equal, 4, 2},
+		{Insert, -1, 3},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("got rows %v, want %v", rows, want)
+	}
+}
+
+func TestInline(t *testing.T) {
+	for _, c := range []struct {
+		a, b string
+		want string
+	}{
+		{"listen 80;", "listen 8080;", "listen [-80-]{+8080+};"},
+		{"root /var/www;", "root /srv/app;", "root /[-var-]{+srv+}/[-www-]{+app+};"},
+		{"a quick brown fox", "a slow red fox", "a [-quick brown-]{+slow red+} fox"},
+		{"gzip on;", "gzip  on;", "gzip[- -]{+  +}on;"},
+		{"你好 世界", "你好 朋友", "你好 [-世界-]{+朋友+}"},
+		{"", "new", "{+new+}"},
+	} {
+		var sb strings.Builder
+		for _, s := range Inline(c.a, c.b) {
+			switch s.Op {
+			case Equal:
+				sb.WriteString(c.a[s.A0:s.A1])
+			case Delete:
+				sb.WriteString("[-" + c.a[s.A0:s.A1] + "-]")
+			case Insert:
+				sb.WriteString("{+" + c.b[s.B0:s.B1] + "+}")
+			}
+		}
+		if sb.String() != c.want {
+			t.Errorf("%q -> %q: got %s, want %s", c.a, c.b, sb.String(), c.want)
+		}
+	}
+}
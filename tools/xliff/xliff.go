@@ -0,0 +1,225 @@
+// Package xliff converts translation files to and from XLIFF 2.0.
+//
+// Each message becomes a unit named after its key, with the source language
+// text as <source> and the translation, if any, as <target>. ICU messages,
+// plurals included, are kept verbatim. Notes of the source file are written
+// as notes applying to the source, notes of the target file as notes applying
+// to the target, with "context" and "comment" categories.
+package xliff
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/uiez/uikit/tools/i18nfile"
+)
+
+const namespace = "urn:oasis:names:tc:xliff:document:2.0"
+
+// Document is a bilingual translation document.
+type Document struct {
+	ID      string // file id, like "app"
+	SrcLang string
+	TrgLang string
+	Source  *i18nfile.File
+	Target  *i18nfile.File // nil for a source only document
+}
+
+type xliffDoc struct {
+	XMLName xml.Name    `xml:"urn:oasis:names:tc:xliff:document:2.0 xliff"`
+	Version string      `xml:"version,attr"`
+	SrcLang string      `xml:"srcLang,attr"`
+	TrgLang string      `xml:"trgLang,attr,omitempty"`
+	Files   []xliffFile `xml:"file"`
+}
+
+type xliffFile struct {
+	ID    string      `xml:"id,attr"`
+	Units []xliffUnit `xml:"unit"`
+}
+
+type xliffUnit struct {
+	ID       string         `xml:"id,attr"`
+	Name     string         `xml:"name,attr,omitempty"`
+	Notes    *xliffNotes    `xml:"notes"`
+	Segments []xliffSegment `xml:"segment"`
+}
+
+type xliffNotes struct {
+	Notes []xliffNote `xml:"note"`
+}
+
+type xliffNote struct {
+	Category  string `xml:"category,attr,omitempty"`
+	AppliesTo string `xml:"appliesTo,attr,omitempty"`
+	Text      string `xml:",chardata"`
+}
+
+type xliffSegment struct {
+	State  string     `xml:"state,attr,omitempty"`
+	Source xliffText  `xml:"source"`
+	Target *xliffText `xml:"target"`
+}
+
+type xliffText struct {
+	Space string `xml:"http://www.w3.org/XML/1998/namespace space,attr,omitempty"`
+	Text  string `xml:",chardata"`
+}
+
+func newText(s string) xliffText {
+	t := xliffText{Text: s}
+	if strings.TrimSpace(s) != s || strings.Contains(s, "\n") || strings.Contains(s, "  ") {
+		t.Space = "preserve"
+	}
+	return t
+}
+
+func notesOf(f *i18nfile.File, key, appliesTo string) []xliffNote {
+	if f == nil {
+		return nil
+	}
+	n := f.Notes[key]
+	var notes []xliffNote
+	if n.Context != "" {
+		notes = append(notes, xliffNote{Category: "context", AppliesTo: appliesTo, Text: n.Context})
+	}
+	if n.Comment != "" {
+		notes = append(notes, xliffNote{Category: "comment", AppliesTo: appliesTo, Text: n.Comment})
+	}
+	return notes
+}
+
+// Encode writes doc as XLIFF 2.0. Keys missing from the source are written
+// with an empty source.
+func Encode(w io.Writer, doc *Document) error {
+	keys := make(map[string]bool)
+	for k := range doc.Source.Messages {
+		keys[k] = true
+	}
+	if doc.Target != nil {
+		for k := range doc.Target.Messages {
+			keys[k] = true
+		}
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	id := doc.ID
+	if id == "" {
+		id = "messages"
+	}
+	file := xliffFile{ID: id}
+	for i, key := range sorted {
+		// unit ids are NMTOKENs, keys are kept in the name
+		u := xliffUnit{ID: "u" + strconv.Itoa(i+1), Name: key}
+		notes := append(notesOf(doc.Source, key, "source"), notesOf(doc.Target, key, "target")...)
+		if len(notes) > 0 {
+			u.Notes = &xliffNotes{Notes: notes}
+		}
+		seg := xliffSegment{Source: newText(doc.Source.Messages[key]), State: "initial"}
+		if doc.Target != nil {
+			if msg, ok := doc.Target.Messages[key]; ok {
+				t := newText(msg)
+				seg.Target = &t
+				if msg != "" {
+					seg.State = "translated"
+				}
+			}
+		}
+		u.Segments = []xliffSegment{seg}
+		file.Units = append(file.Units, u)
+	}
+	x := xliffDoc{Version: "2.0", SrcLang: doc.SrcLang, Files: []xliffFile{file}}
+	if doc.Target != nil {
+		x.TrgLang = doc.TrgLang
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(x); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func addNotes(f *i18nfile.File, key string, notes []xliffNote, appliesTo string) {
+	for _, n := range notes {
+		if n.AppliesTo != appliesTo && !(appliesTo == "source" && n.AppliesTo == "") {
+			continue
+		}
+		note := f.Notes[key]
+		switch n.Category {
+		case "context":
+			note.Context = n.Text
+		default:
+			if note.Comment != "" {
+				note.Comment += "\n"
+			}
+			note.Comment += n.Text
+		}
+		f.Notes[key] = note
+	}
+}
+
+// Decode reads an XLIFF 2.0 document. Segments of a unit are concatenated.
+// Target is nil if the document has no target language.
+func Decode(r io.Reader) (*Document, error) {
+	var x xliffDoc
+	if err := xml.NewDecoder(r).Decode(&x); err != nil {
+		return nil, err
+	}
+	if x.XMLName.Space != namespace {
+		return nil, fmt.Errorf("xliff: unsupported namespace %q", x.XMLName.Space)
+	}
+	if !strings.HasPrefix(x.Version, "2.") {
+		return nil, fmt.Errorf("xliff: unsupported version %q", x.Version)
+	}
+	doc := &Document{SrcLang: x.SrcLang, TrgLang: x.TrgLang, Source: i18nfile.New()}
+	if x.TrgLang != "" {
+		doc.Target = i18nfile.New()
+	}
+	for _, file := range x.Files {
+		if doc.ID == "" {
+			doc.ID = file.ID
+		}
+		for _, u := range file.Units {
+			key := u.Name
+			if key == "" {
+				key = u.ID
+			}
+			var (
+				source, target strings.Builder
+				hasTarget      bool
+			)
+			for _, seg := range u.Segments {
+				source.WriteString(seg.Source.Text)
+				if seg.Target != nil {
+					hasTarget = true
+					target.WriteString(seg.Target.Text)
+				}
+			}
+			doc.Source.Messages[key] = source.String()
+			if doc.Target != nil && hasTarget {
+				doc.Target.Messages[key] = target.String()
+			}
+			if u.Notes != nil {
+				addNotes(doc.Source, key, u.Notes.Notes, "source")
+				if doc.Target != nil {
+					addNotes(doc.Target, key, u.Notes.Notes, "target")
+				}
+			}
+		}
+	}
+	return doc, nil
+}
@@ -0,0 +1,92 @@
+package xliff
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/uiez/uikit/tools/i18nfile"
+)
+
+// loadExample loads the shipped example translations and adds a plural
+// message and notes on both sides.
+func loadExample(t *testing.T) (en, zh *i18nfile.File) {
+	var err error
+	if en, err = i18nfile.Load("../../example/assets/i18n/app-en.json"); err != nil {
+		t.Fatal(err)
+	}
+	if zh, err = i18nfile.Load("../../example/assets/i18n/app-zh.json"); err != nil {
+		t.Fatal(err)
+	}
+	en.Messages["files"] = "{count, plural, one {# file} other {# files}}"
+	zh.Messages["files"] = "{count, plural, other {# 个文件}}"
+	en.Notes["hello"] = i18nfile.Note{Context: "window title", Comment: "Shown on start.\nKeep it short."}
+	zh.Notes["hello"] = i18nfile.Note{Comment: "  reviewed <b>twice</b>"}
+	en.Notes["files"] = i18nfile.Note{Context: "status bar"}
+	return en, zh
+}
+
+func TestRoundTrip(t *testing.T) {
+	en, zh := loadExample(t)
+	var buf bytes.Buffer
+	err := Encode(&buf, &Document{ID: "app", SrcLang: "en", TrgLang: "zh", Source: en, Target: zh})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		`<xliff xmlns="urn:oasis:names:tc:xliff:document:2.0" version="2.0" srcLang="en" trgLang="zh">`,
+		`<unit id="u2" name="hello">`,
+		`<note category="context" appliesTo="source">window title</note>`,
+		`<segment state="translated">`,
+		`<target>{count, plural, other {# 个文件}}</target>`,
+	} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("output does not contain %s:\n%s", s, buf.String())
+		}
+	}
+
+	doc, err := Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.ID != "app" || doc.SrcLang != "en" || doc.TrgLang != "zh" {
+		t.Errorf("unexpected document %+v", doc)
+	}
+	if !reflect.DeepEqual(doc.Source, en) {
+		t.Errorf("source changed: %+v != %+v", doc.Source, en)
+	}
+	if !reflect.DeepEqual(doc.Target, zh) {
+		t.Errorf("target changed: %+v != %+v", doc.Target, zh)
+	}
+}
+
+func TestSourceOnly(t *testing.T) {
+	en, _ := loadExample(t)
+	var buf bytes.Buffer
+	if err := Encode(&buf, &Document{SrcLang: "en", Source: en}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "trgLang") || strings.Contains(buf.String(), "<target") {
+		t.Errorf("unexpected target in\n%s", buf.String())
+	}
+	doc, err := Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Target != nil || !reflect.DeepEqual(doc.Source, en) {
+		t.Errorf("unexpected document %+v", doc)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, s := range []string{
+		`<xliff xmlns="urn:oasis:names:tc:xliff:document:1.2" version="1.2"/>`,
+		`<xliff xmlns="urn:oasis:names:tc:xliff:document:2.0" version="3.0"/>`,
+		`<xliff`,
+	} {
+		if _, err := Decode(strings.NewReader(s)); err == nil {
+			t.Errorf("%s: no error", s)
+		}
+	}
+}